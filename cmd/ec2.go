@@ -29,6 +29,22 @@ func (a *MethodAws) InitEc2Command() {
 		},
 	}
 
+	publicIPsCmd := &cobra.Command{
+		Use:   "public-ips",
+		Short: "Enumerate public IP addresses",
+		Long:  `Enumerate every public IP address owned by the account and the resource it maps to`,
+		Run: func(cmd *cobra.Command, args []string) {
+			report, err := ec2.EnumeratePublicIPs(cmd.Context(), *a.AwsConfig, a.RootFlags.Regions)
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
 	ec2Cmd.AddCommand(enumerateCmd)
+	ec2Cmd.AddCommand(publicIPsCmd)
 	a.RootCmd.AddCommand(ec2Cmd)
 }
@@ -5,8 +5,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// InitIamCommand initializes the `methodaws iam` subcommand that deals with enumerating IAM roles, attached policies,
-// inline policies and assume role policies within the AWS account.
+// InitIamCommand initializes the `methodaws iam` subcommand that deals with enumerating IAM roles, users, groups,
+// attached policies, inline policies and assume role policies within the AWS account.
 func (a *MethodAws) InitIamCommand() {
 	iamCmd := &cobra.Command{
 		Use:   "iam",
@@ -19,7 +19,7 @@ func (a *MethodAws) InitIamCommand() {
 		Short: "Enumerate IAM resources",
 		Long:  `Enumerate IAM resources`,
 		Run: func(cmd *cobra.Command, args []string) {
-			report, err := iam.EnumerateIamRoles(cmd.Context(), *a.AwsConfig)
+			report, err := iam.EnumerateIam(cmd.Context(), *a.AwsConfig)
 			if err != nil {
 				errorMessage := err.Error()
 				a.OutputSignal.ErrorMessage = &errorMessage
@@ -29,6 +29,188 @@ func (a *MethodAws) InitIamCommand() {
 		},
 	}
 
+	credentialReportCmd := &cobra.Command{
+		Use:   "credential-report",
+		Short: "Audit IAM users with the credential report",
+		Long:  `Generate and parse the IAM credential report, flagging root usage, stale access keys and console users without MFA.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			staleDays, err := cmd.Flags().GetInt("stale-days")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			report, err := iam.GetCredentialReport(cmd.Context(), *a.AwsConfig, staleDays)
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	credentialReportCmd.Flags().Int("stale-days", 90, "Number of days used to flag access keys that have not been rotated or used, and recent root user activity")
+
+	simulateCmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Simulate whether a principal is allowed to perform an action",
+		Long:  `Evaluate offline whether an IAM role or user is allowed to perform an action on a resource, using its identity policies and permission boundary along with optional resource and service control policies.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			principal, err := cmd.Flags().GetString("principal")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			action, err := cmd.Flags().GetString("action")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			resource, err := cmd.Flags().GetString("resource")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			contextValues, err := cmd.Flags().GetStringArray("context")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			resourceAccount, err := cmd.Flags().GetString("resource-account")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			resourcePolicyFile, err := cmd.Flags().GetString("resource-policy-file")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			scpFiles, err := cmd.Flags().GetStringArray("scp-file")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			options := iam.SimulateOptions{
+				Context:            contextValues,
+				ResourceAccount:    resourceAccount,
+				ResourcePolicyFile: resourcePolicyFile,
+				SCPFiles:           scpFiles,
+			}
+			report, err := iam.Simulate(cmd.Context(), *a.AwsConfig, principal, action, resource, options)
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	simulateCmd.Flags().String("principal", "", "ARN of the IAM role, user or assumed role session making the request")
+	simulateCmd.Flags().String("action", "", "Action to simulate, e.g. s3:GetObject")
+	simulateCmd.Flags().String("resource", "*", "ARN of the resource the action is performed on")
+	simulateCmd.Flags().StringArray("context", []string{}, "Condition key value given as key=value, e.g. aws:SourceIp=10.0.0.1. You can specify multiple values by providing the flag multiple times.")
+	simulateCmd.Flags().String("resource-account", "", "ID of the account that owns the resource, needed to evaluate cross-account requests on resources whose ARN has no account, such as S3 buckets and objects")
+	simulateCmd.Flags().String("resource-policy-file", "", "Path to a JSON resource policy to evaluate alongside the principal's policies")
+	simulateCmd.Flags().StringArray("scp-file", []string{}, "Path to a JSON service control policy that applies to the principal's account. You can specify multiple policies by providing the flag multiple times.")
+	_ = simulateCmd.MarkFlagRequired("principal")
+	_ = simulateCmd.MarkFlagRequired("action")
+
+	privescCmd := &cobra.Command{
+		Use:   "privesc",
+		Short: "Detect IAM privilege escalation paths",
+		Long:  `Detect the privilege escalation paths from IAM roles and users to admin-equivalent principals, such as passing a role to a new EC2 instance or Lambda function, creating a new policy version, or assuming a chain of roles.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			principals, err := cmd.Flags().GetStringArray("principal")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			report, err := iam.DetectPrivilegeEscalation(cmd.Context(), *a.AwsConfig, principals)
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	privescCmd.Flags().StringArray("principal", []string{}, "ARN of an IAM role or user to report escalation paths from. You can specify multiple principals by providing the flag multiple times. If blank, will report paths from every principal.")
+
+	trustCmd := &cobra.Command{
+		Use:   "trust",
+		Short: "Analyze IAM role trust policies",
+		Long:  `Analyze the trust policy of every IAM role, classifying the trusted principals, flagging missing external IDs and broad OIDC subjects, and building an account-to-role trust graph.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			report, err := iam.AnalyzeTrust(cmd.Context(), *a.AwsConfig)
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	unusedCmd := &cobra.Command{
+		Use:   "unused",
+		Short: "Find unused IAM roles, users and service access",
+		Long:  `Find the IAM roles and users that have not been used recently, and the services their policies grant that they never accessed, using IAM last accessed data.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			unusedDays, err := cmd.Flags().GetInt("unused-days")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			report, err := iam.FindUnusedAccess(cmd.Context(), *a.AwsConfig, unusedDays)
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	unusedCmd.Flags().Int("unused-days", 90, "Number of days without use after which a principal or service is reported as unused")
+
 	iamCmd.AddCommand(enumerateCmd)
+	iamCmd.AddCommand(credentialReportCmd)
+	iamCmd.AddCommand(simulateCmd)
+	iamCmd.AddCommand(privescCmd)
+	iamCmd.AddCommand(trustCmd)
+	iamCmd.AddCommand(unusedCmd)
 	a.RootCmd.AddCommand(iamCmd)
 }
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/Method-Security/methodaws/internal/network"
+	"github.com/spf13/cobra"
+)
+
+// InitNetworkCommand initializes the `methodaws network` subcommand that deals with analyzing how resources in the AWS
+// account can be reached over the network.
+func (a *MethodAws) InitNetworkCommand() {
+	networkCmd := &cobra.Command{
+		Use:   "network",
+		Short: "Analyze network exposure of AWS resources",
+		Long:  `Analyze network exposure of AWS resources`,
+	}
+
+	reachabilityCmd := &cobra.Command{
+		Use:   "reachability",
+		Short: "Find resources reachable from the internet",
+		Long:  `Find EC2 instances, RDS instances and load balancers that are reachable from the internet, and explain the path that makes them reachable.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			report, err := network.EnumerateReachability(cmd.Context(), *a.AwsConfig, a.RootFlags.Regions)
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	networkCmd.AddCommand(reachabilityCmd)
+	a.RootCmd.AddCommand(networkCmd)
+}
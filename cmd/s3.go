@@ -18,11 +18,30 @@ func (a *MethodAws) InitS3Command() {
 		Short: "Enumerate all S3 buckets",
 		Long:  `Enumerate all S3 buckets in your AWS account.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			report := s3.EnumerateS3(cmd.Context(), *a.AwsConfig, a.RootFlags.Regions)
+			bucketNames, err := cmd.Flags().GetStringArray("bucket")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			bucketPrefix, err := cmd.Flags().GetString("bucket-prefix")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			report := s3.EnumerateS3(cmd.Context(), *a.AwsConfig, a.RootFlags.Regions, bucketNames, bucketPrefix)
 			a.OutputSignal.Content = report
 		},
 	}
 
+	enumerateCmd.Flags().StringArray("bucket", []string{}, "Name of an S3 bucket to enumerate. You can specify multiple buckets by providing the flag multiple times. If blank, will enumerate all buckets.")
+	enumerateCmd.Flags().String("bucket-prefix", "", "Only enumerate S3 buckets whose names start with this prefix")
+
 	lsCmd := &cobra.Command{
 		Use:   "ls",
 		Short: "List all objects in a single S3 bucket",
@@ -36,7 +55,54 @@ func (a *MethodAws) InitS3Command() {
 				return
 			}
 
-			report, err := s3.LsS3Bucket(cmd.Context(), *a.AwsConfig, bucketName)
+			prefix, err := cmd.Flags().GetString("prefix")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			delimiter, err := cmd.Flags().GetString("delimiter")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			maxKeys, err := cmd.Flags().GetInt("max-keys")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			includeVersions, err := cmd.Flags().GetBool("include-versions")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			includeMetadata, err := cmd.Flags().GetBool("include-metadata")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			options := s3.LsOptions{
+				Prefix:          prefix,
+				Delimiter:       delimiter,
+				MaxKeys:         maxKeys,
+				IncludeVersions: includeVersions,
+				IncludeMetadata: includeMetadata,
+			}
+			report, err := s3.LsS3Bucket(cmd.Context(), *a.AwsConfig, bucketName, options)
 			if err != nil {
 				errorMessage := err.Error()
 				a.OutputSignal.ErrorMessage = &errorMessage
@@ -47,11 +113,83 @@ func (a *MethodAws) InitS3Command() {
 	}
 
 	lsCmd.Flags().String("name", "", "Name of the S3 bucket")
+	lsCmd.Flags().String("prefix", "", "Only list keys that start with this prefix")
+	lsCmd.Flags().String("delimiter", "", "Roll up keys that share a prefix up to this delimiter into common prefixes, e.g. /")
+	lsCmd.Flags().Int("max-keys", 0, "Maximum number of keys to list. If 0, will list every key.")
+	lsCmd.Flags().Bool("include-versions", false, "List every object version and delete marker")
+	lsCmd.Flags().Bool("include-metadata", false, "Include the storage class, ETag, last-modified time and owner of each object")
+
+	scanCmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Scan the first objects listed in a single S3 bucket for sensitive data",
+		Long:  `Scan the first objects listed in a single S3 bucket, up to the sample size, for credentials, private keys, email addresses, credit card numbers and configuration files. Listing stops once the sample is collected.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			bucketName, err := cmd.Flags().GetString("name")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			prefix, err := cmd.Flags().GetString("prefix")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			sampleSize, err := cmd.Flags().GetInt("sample-size")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			maxObjectSize, err := cmd.Flags().GetInt64("max-object-size")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			keyPatterns, err := cmd.Flags().GetStringArray("key-pattern")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			options := s3.ScanOptions{
+				Prefix:        prefix,
+				SampleSize:    sampleSize,
+				MaxObjectSize: maxObjectSize,
+				KeyPatterns:   keyPatterns,
+			}
+			report, err := s3.ScanS3Bucket(cmd.Context(), *a.AwsConfig, bucketName, options)
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	scanCmd.Flags().String("name", "", "Name of the S3 bucket")
+	scanCmd.Flags().String("prefix", "", "Only scan keys that start with this prefix")
+	scanCmd.Flags().Int("sample-size", 100, "Number of non-empty objects to sample before listing stops")
+	scanCmd.Flags().Int64("max-object-size", 1024*1024, "Maximum number of bytes to read from each object. Larger objects are scanned up to this size and marked truncated.")
+	scanCmd.Flags().StringArray("key-pattern", []string{}, "Only scan keys matching this pattern, which may contain * and ? wildcards. You can specify multiple patterns by providing the flag multiple times.")
 
 	externalEnumerateCmd := &cobra.Command{
 		Use:   "externalenumerate",
-		Short: "Enumerate a single public facing S3 bucket.",
-		Long:  `Enumerate a single public facing S3 bucket with no credentials.`,
+		Short: "Enumerate public facing S3 buckets.",
+		Long:  `Enumerate a single public facing S3 bucket, or discover public facing S3 buckets from seed names and a wordlist, with no credentials.`,
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
 			outputFormat, err := cmd.Flags().GetString("output")
 			if err != nil {
@@ -61,7 +199,11 @@ func (a *MethodAws) InitS3Command() {
 			if err != nil {
 				return err
 			}
-			return a.setupCommonConfig(cmd, outputFormat, outputFile, false)
+			authenticated, err := cmd.Flags().GetBool("authenticated")
+			if err != nil {
+				return err
+			}
+			return a.setupCommonConfig(cmd, outputFormat, outputFile, authenticated)
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			bucketName, err := cmd.Flags().GetString("name")
@@ -71,15 +213,79 @@ func (a *MethodAws) InitS3Command() {
 				a.OutputSignal.Status = 1
 				return
 			}
-			report := s3.ExternalEnumerateS3(cmd.Context(), bucketName, a.RootFlags.Regions)
+
+			seeds, err := cmd.Flags().GetStringArray("seed")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			wordlistPath, err := cmd.Flags().GetString("wordlist")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			checkWrite, err := cmd.Flags().GetBool("check-write")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+			if checkWrite && len(seeds) > 0 {
+				errorMessage := "--check-write cannot be used with --seed, as discovered buckets may belong to anyone"
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			authenticated, err := cmd.Flags().GetBool("authenticated")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			options := s3.ExternalOptions{CheckWrite: checkWrite}
+			if authenticated {
+				options.AuthenticatedConfig = a.AwsConfig
+			}
+			if len(seeds) == 0 {
+				report := s3.ExternalEnumerateS3(cmd.Context(), bucketName, options)
+				a.OutputSignal.Content = report
+				return
+			}
+
+			words := []string{}
+			if wordlistPath != "" {
+				words, err = s3.LoadWordlist(wordlistPath)
+				if err != nil {
+					errorMessage := err.Error()
+					a.OutputSignal.ErrorMessage = &errorMessage
+					a.OutputSignal.Status = 1
+					return
+				}
+			}
+			report := s3.DiscoverExternalS3(cmd.Context(), seeds, words, options)
 			a.OutputSignal.Content = report
 		},
 	}
 
 	externalEnumerateCmd.Flags().String("name", "", "Name of the S3 bucket")
+	externalEnumerateCmd.Flags().StringArray("seed", []string{}, "Company name or domain to generate candidate bucket names from, instead of a single --name. You can specify multiple seeds by providing the flag multiple times.")
+	externalEnumerateCmd.Flags().Bool("authenticated", false, "Repeat the read-only checks with your own AWS credentials to find buckets exposed to any authenticated AWS user. Use credentials from an account other than the bucket owner's.")
+	externalEnumerateCmd.Flags().Bool("check-write", false, "Test anonymous write access by uploading and deleting a canary object and re-applying the bucket ACL unchanged. This modifies the target bucket and cannot be used with --seed.")
+	externalEnumerateCmd.Flags().String("wordlist", "", "Path to a file with one word per line to combine with the seeds. If blank, will use a built-in list of common words.")
 
 	s3Cmd.AddCommand(enumerateCmd)
 	s3Cmd.AddCommand(lsCmd)
+	s3Cmd.AddCommand(scanCmd)
 	s3Cmd.AddCommand(externalEnumerateCmd)
 	a.RootCmd.AddCommand(s3Cmd)
 }
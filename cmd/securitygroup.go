@@ -35,7 +35,15 @@ func (a *MethodAws) InitSecurityGroupCommand() {
 				vpcID = nil
 			}
 
-			report, err := ec2.EnumerateSecurityGroups(cmd.Context(), *a.AwsConfig, vpcID, a.RootFlags.Regions)
+			analyze, err := cmd.Flags().GetBool("analyze")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			report, err := ec2.EnumerateSecurityGroups(cmd.Context(), *a.AwsConfig, vpcID, a.RootFlags.Regions, analyze)
 			if err != nil {
 				errorMessage := err.Error()
 				a.OutputSignal.ErrorMessage = &errorMessage
@@ -46,7 +54,39 @@ func (a *MethodAws) InitSecurityGroupCommand() {
 	}
 
 	enumerateCmd.Flags().String("vpc", "", "VPC ID to filter security groups by")
+	enumerateCmd.Flags().Bool("analyze", false, "Normalize rules, flag internet exposed sensitive ports and resolve attachments")
+
+	unusedCmd := &cobra.Command{
+		Use:   "unused",
+		Short: "Find unused security groups",
+		Long:  `Find security groups that are not attached to any network interface and default security groups that still have rules`,
+		Run: func(cmd *cobra.Command, args []string) {
+			var vpcID *string
+			vpcIDFlag, err := cmd.Flags().GetString("vpc")
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+				return
+			}
+
+			if vpcIDFlag != "" {
+				vpcID = &vpcIDFlag
+			}
+
+			report, err := ec2.FindUnusedSecurityGroups(cmd.Context(), *a.AwsConfig, vpcID, a.RootFlags.Regions)
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	unusedCmd.Flags().String("vpc", "", "VPC ID to filter security groups by")
 
 	securityGroupCmd.AddCommand(enumerateCmd)
+	securityGroupCmd.AddCommand(unusedCmd)
 	a.RootCmd.AddCommand(securityGroupCmd)
 }
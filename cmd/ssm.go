@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/Method-Security/methodaws/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+// InitSSMCommand initializes the `methodaws ssm` subcommand that deals with enumerating Systems Manager managed
+// instances, shared documents and parameters in the AWS account.
+func (a *MethodAws) InitSSMCommand() {
+	ssmCmd := &cobra.Command{
+		Use:   "ssm",
+		Short: "Audit and manage Systems Manager resources",
+		Long:  `Audit and manage Systems Manager resources`,
+	}
+
+	enumerateCmd := &cobra.Command{
+		Use:   "enumerate",
+		Short: "Enumerate Systems Manager resources",
+		Long:  `Enumerate Systems Manager managed instances, shared documents and parameters in your AWS account.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			report, err := ssm.EnumerateSSM(cmd.Context(), *a.AwsConfig, a.RootFlags.Regions)
+			if err != nil {
+				errorMessage := err.Error()
+				a.OutputSignal.ErrorMessage = &errorMessage
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	ssmCmd.AddCommand(enumerateCmd)
+	a.RootCmd.AddCommand(ssmCmd)
+}
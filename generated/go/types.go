@@ -101,6 +101,284 @@ func (c *CredentialReport) String() string {
 	return fmt.Sprintf("%#v", c)
 }
 
+type IamCredentialReport struct {
+	AccountId     string                     `json:"accountId" url:"accountId"`
+	GeneratedTime *time.Time                 `json:"generatedTime,omitempty" url:"generatedTime,omitempty"`
+	Users         []*IamCredentialReportUser `json:"users,omitempty" url:"users,omitempty"`
+	Errors        []string                   `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (i *IamCredentialReport) GetExtraProperties() map[string]interface{} {
+	return i.extraProperties
+}
+
+func (i *IamCredentialReport) UnmarshalJSON(data []byte) error {
+	type embed IamCredentialReport
+	var unmarshaler = struct {
+		embed
+		GeneratedTime *core.DateTime `json:"generatedTime,omitempty"`
+	}{
+		embed: embed(*i),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*i = IamCredentialReport(unmarshaler.embed)
+	i.GeneratedTime = unmarshaler.GeneratedTime.TimePtr()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *i)
+	if err != nil {
+		return err
+	}
+	i.extraProperties = extraProperties
+
+	return nil
+}
+
+func (i *IamCredentialReport) MarshalJSON() ([]byte, error) {
+	type embed IamCredentialReport
+	var marshaler = struct {
+		embed
+		GeneratedTime *core.DateTime `json:"generatedTime,omitempty"`
+	}{
+		embed:         embed(*i),
+		GeneratedTime: core.NewOptionalDateTime(i.GeneratedTime),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (i *IamCredentialReport) String() string {
+	if value, err := core.StringifyJSON(i); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", i)
+}
+
+type IamCredentialReportAccessKey struct {
+	Active          bool       `json:"active" url:"active"`
+	LastRotated     *time.Time `json:"lastRotated,omitempty" url:"lastRotated,omitempty"`
+	AgeDays         *int       `json:"ageDays,omitempty" url:"ageDays,omitempty"`
+	LastUsedDate    *time.Time `json:"lastUsedDate,omitempty" url:"lastUsedDate,omitempty"`
+	LastUsedRegion  *string    `json:"lastUsedRegion,omitempty" url:"lastUsedRegion,omitempty"`
+	LastUsedService *string    `json:"lastUsedService,omitempty" url:"lastUsedService,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (i *IamCredentialReportAccessKey) GetExtraProperties() map[string]interface{} {
+	return i.extraProperties
+}
+
+func (i *IamCredentialReportAccessKey) UnmarshalJSON(data []byte) error {
+	type embed IamCredentialReportAccessKey
+	var unmarshaler = struct {
+		embed
+		LastRotated  *core.DateTime `json:"lastRotated,omitempty"`
+		LastUsedDate *core.DateTime `json:"lastUsedDate,omitempty"`
+	}{
+		embed: embed(*i),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*i = IamCredentialReportAccessKey(unmarshaler.embed)
+	i.LastRotated = unmarshaler.LastRotated.TimePtr()
+	i.LastUsedDate = unmarshaler.LastUsedDate.TimePtr()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *i)
+	if err != nil {
+		return err
+	}
+	i.extraProperties = extraProperties
+
+	return nil
+}
+
+func (i *IamCredentialReportAccessKey) MarshalJSON() ([]byte, error) {
+	type embed IamCredentialReportAccessKey
+	var marshaler = struct {
+		embed
+		LastRotated  *core.DateTime `json:"lastRotated,omitempty"`
+		LastUsedDate *core.DateTime `json:"lastUsedDate,omitempty"`
+	}{
+		embed:        embed(*i),
+		LastRotated:  core.NewOptionalDateTime(i.LastRotated),
+		LastUsedDate: core.NewOptionalDateTime(i.LastUsedDate),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (i *IamCredentialReportAccessKey) String() string {
+	if value, err := core.StringifyJSON(i); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", i)
+}
+
+type IamCredentialReportCertificate struct {
+	Active      bool       `json:"active" url:"active"`
+	LastRotated *time.Time `json:"lastRotated,omitempty" url:"lastRotated,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (i *IamCredentialReportCertificate) GetExtraProperties() map[string]interface{} {
+	return i.extraProperties
+}
+
+func (i *IamCredentialReportCertificate) UnmarshalJSON(data []byte) error {
+	type embed IamCredentialReportCertificate
+	var unmarshaler = struct {
+		embed
+		LastRotated *core.DateTime `json:"lastRotated,omitempty"`
+	}{
+		embed: embed(*i),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*i = IamCredentialReportCertificate(unmarshaler.embed)
+	i.LastRotated = unmarshaler.LastRotated.TimePtr()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *i)
+	if err != nil {
+		return err
+	}
+	i.extraProperties = extraProperties
+
+	return nil
+}
+
+func (i *IamCredentialReportCertificate) MarshalJSON() ([]byte, error) {
+	type embed IamCredentialReportCertificate
+	var marshaler = struct {
+		embed
+		LastRotated *core.DateTime `json:"lastRotated,omitempty"`
+	}{
+		embed:       embed(*i),
+		LastRotated: core.NewOptionalDateTime(i.LastRotated),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (i *IamCredentialReportCertificate) String() string {
+	if value, err := core.StringifyJSON(i); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", i)
+}
+
+type IamCredentialReportFinding string
+
+const (
+	IamCredentialReportFindingRootAccessKeyActive   IamCredentialReportFinding = "ROOT_ACCESS_KEY_ACTIVE"
+	IamCredentialReportFindingRootMfaDisabled       IamCredentialReportFinding = "ROOT_MFA_DISABLED"
+	IamCredentialReportFindingRootRecentlyUsed      IamCredentialReportFinding = "ROOT_RECENTLY_USED"
+	IamCredentialReportFindingStaleAccessKey        IamCredentialReportFinding = "STALE_ACCESS_KEY"
+	IamCredentialReportFindingUnusedAccessKey       IamCredentialReportFinding = "UNUSED_ACCESS_KEY"
+	IamCredentialReportFindingConsoleUserWithoutMfa IamCredentialReportFinding = "CONSOLE_USER_WITHOUT_MFA"
+)
+
+func NewIamCredentialReportFindingFromString(s string) (IamCredentialReportFinding, error) {
+	switch s {
+	case "ROOT_ACCESS_KEY_ACTIVE":
+		return IamCredentialReportFindingRootAccessKeyActive, nil
+	case "ROOT_MFA_DISABLED":
+		return IamCredentialReportFindingRootMfaDisabled, nil
+	case "ROOT_RECENTLY_USED":
+		return IamCredentialReportFindingRootRecentlyUsed, nil
+	case "STALE_ACCESS_KEY":
+		return IamCredentialReportFindingStaleAccessKey, nil
+	case "UNUSED_ACCESS_KEY":
+		return IamCredentialReportFindingUnusedAccessKey, nil
+	case "CONSOLE_USER_WITHOUT_MFA":
+		return IamCredentialReportFindingConsoleUserWithoutMfa, nil
+	}
+	var t IamCredentialReportFinding
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (i IamCredentialReportFinding) Ptr() *IamCredentialReportFinding {
+	return &i
+}
+
+type IamCredentialReportUser struct {
+	User                 string                            `json:"user" url:"user"`
+	Arn                  string                            `json:"arn" url:"arn"`
+	IsRoot               bool                              `json:"isRoot" url:"isRoot"`
+	UserCreationTime     *time.Time                        `json:"userCreationTime,omitempty" url:"userCreationTime,omitempty"`
+	PasswordEnabled      bool                              `json:"passwordEnabled" url:"passwordEnabled"`
+	PasswordLastUsed     *time.Time                        `json:"passwordLastUsed,omitempty" url:"passwordLastUsed,omitempty"`
+	PasswordLastChanged  *time.Time                        `json:"passwordLastChanged,omitempty" url:"passwordLastChanged,omitempty"`
+	PasswordNextRotation *time.Time                        `json:"passwordNextRotation,omitempty" url:"passwordNextRotation,omitempty"`
+	MfaActive            bool                              `json:"mfaActive" url:"mfaActive"`
+	AccessKeys           []*IamCredentialReportAccessKey   `json:"accessKeys" url:"accessKeys"`
+	Certificates         []*IamCredentialReportCertificate `json:"certificates" url:"certificates"`
+	Findings             []IamCredentialReportFinding      `json:"findings" url:"findings"`
+
+	extraProperties map[string]interface{}
+}
+
+func (i *IamCredentialReportUser) GetExtraProperties() map[string]interface{} {
+	return i.extraProperties
+}
+
+func (i *IamCredentialReportUser) UnmarshalJSON(data []byte) error {
+	type embed IamCredentialReportUser
+	var unmarshaler = struct {
+		embed
+		UserCreationTime     *core.DateTime `json:"userCreationTime,omitempty"`
+		PasswordLastUsed     *core.DateTime `json:"passwordLastUsed,omitempty"`
+		PasswordLastChanged  *core.DateTime `json:"passwordLastChanged,omitempty"`
+		PasswordNextRotation *core.DateTime `json:"passwordNextRotation,omitempty"`
+	}{
+		embed: embed(*i),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*i = IamCredentialReportUser(unmarshaler.embed)
+	i.UserCreationTime = unmarshaler.UserCreationTime.TimePtr()
+	i.PasswordLastUsed = unmarshaler.PasswordLastUsed.TimePtr()
+	i.PasswordLastChanged = unmarshaler.PasswordLastChanged.TimePtr()
+	i.PasswordNextRotation = unmarshaler.PasswordNextRotation.TimePtr()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *i)
+	if err != nil {
+		return err
+	}
+	i.extraProperties = extraProperties
+
+	return nil
+}
+
+func (i *IamCredentialReportUser) MarshalJSON() ([]byte, error) {
+	type embed IamCredentialReportUser
+	var marshaler = struct {
+		embed
+		UserCreationTime     *core.DateTime `json:"userCreationTime,omitempty"`
+		PasswordLastUsed     *core.DateTime `json:"passwordLastUsed,omitempty"`
+		PasswordLastChanged  *core.DateTime `json:"passwordLastChanged,omitempty"`
+		PasswordNextRotation *core.DateTime `json:"passwordNextRotation,omitempty"`
+	}{
+		embed:                embed(*i),
+		UserCreationTime:     core.NewOptionalDateTime(i.UserCreationTime),
+		PasswordLastUsed:     core.NewOptionalDateTime(i.PasswordLastUsed),
+		PasswordLastChanged:  core.NewOptionalDateTime(i.PasswordLastChanged),
+		PasswordNextRotation: core.NewOptionalDateTime(i.PasswordNextRotation),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (i *IamCredentialReportUser) String() string {
+	if value, err := core.StringifyJSON(i); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", i)
+}
+
 type Certificate struct {
 	Arn       string `json:"arn" url:"arn"`
 	IsDefault bool   `json:"isDefault" url:"isDefault"`
@@ -554,18 +832,29 @@ func (t TargetType) Ptr() *TargetType {
 }
 
 type Bucket struct {
-	Name               string                            `json:"name" url:"name"`
-	Arn                string                            `json:"arn" url:"arn"`
-	Url                string                            `json:"url" url:"url"`
-	Region             string                            `json:"region" url:"region"`
-	CreationDate       time.Time                         `json:"creationDate" url:"creationDate"`
-	OwnerId            string                            `json:"ownerID" url:"ownerID"`
-	OwnerName          string                            `json:"ownerName" url:"ownerName"`
-	PublicAccessConfig *S3PublicAccessBlockConfiguration `json:"publicAccessConfig,omitempty" url:"publicAccessConfig,omitempty"`
-	Policy             *string                           `json:"policy,omitempty" url:"policy,omitempty"`
-	BucketVersioning   *BucketVersioningStatus           `json:"bucketVersioning,omitempty" url:"bucketVersioning,omitempty"`
-	MfaDelete          *S3MfaDeleteStatus                `json:"mfaDelete,omitempty" url:"mfaDelete,omitempty"`
-	EncryptionRules    []*EncryptionRule                 `json:"encryptionRules,omitempty" url:"encryptionRules,omitempty"`
+	Name                        string                            `json:"name" url:"name"`
+	Arn                         string                            `json:"arn" url:"arn"`
+	Url                         string                            `json:"url" url:"url"`
+	Region                      string                            `json:"region" url:"region"`
+	CreationDate                time.Time                         `json:"creationDate" url:"creationDate"`
+	OwnerId                     string                            `json:"ownerID" url:"ownerID"`
+	OwnerName                   string                            `json:"ownerName" url:"ownerName"`
+	PublicAccessConfig          *S3PublicAccessBlockConfiguration `json:"publicAccessConfig,omitempty" url:"publicAccessConfig,omitempty"`
+	EffectivePublicAccessConfig *S3PublicAccessBlockConfiguration `json:"effectivePublicAccessConfig,omitempty" url:"effectivePublicAccessConfig,omitempty"`
+	Policy                      *string                           `json:"policy,omitempty" url:"policy,omitempty"`
+	BucketVersioning            *BucketVersioningStatus           `json:"bucketVersioning,omitempty" url:"bucketVersioning,omitempty"`
+	MfaDelete                   *S3MfaDeleteStatus                `json:"mfaDelete,omitempty" url:"mfaDelete,omitempty"`
+	EncryptionRules             []*EncryptionRule                 `json:"encryptionRules,omitempty" url:"encryptionRules,omitempty"`
+	Logging                     *S3LoggingConfiguration           `json:"logging,omitempty" url:"logging,omitempty"`
+	LifecycleRules              []*S3LifecycleRule                `json:"lifecycleRules,omitempty" url:"lifecycleRules,omitempty"`
+	Replication                 *S3ReplicationConfiguration       `json:"replication,omitempty" url:"replication,omitempty"`
+	Website                     *S3WebsiteConfiguration           `json:"website,omitempty" url:"website,omitempty"`
+	CorsRules                   []*S3CorsRule                     `json:"corsRules,omitempty" url:"corsRules,omitempty"`
+	ObjectLock                  *S3ObjectLockConfiguration        `json:"objectLock,omitempty" url:"objectLock,omitempty"`
+	ObjectOwnership             *S3ObjectOwnership                `json:"objectOwnership,omitempty" url:"objectOwnership,omitempty"`
+	AclGrants                   []*S3BucketGrant                  `json:"aclGrants,omitempty" url:"aclGrants,omitempty"`
+	AccessAnalysis              *S3AccessAnalysis                 `json:"accessAnalysis,omitempty" url:"accessAnalysis,omitempty"`
+	Errors                      []string                          `json:"errors,omitempty" url:"errors,omitempty"`
 
 	extraProperties map[string]interface{}
 }
@@ -674,14 +963,19 @@ func (e *EncryptionRule) String() string {
 }
 
 type ExternalBucket struct {
-	Name                  string             `json:"name" url:"name"`
-	Url                   string             `json:"url" url:"url"`
-	Region                string             `json:"region" url:"region"`
-	DirectoryContents     []*S3ObjectDetails `json:"directoryContents,omitempty" url:"directoryContents,omitempty"`
-	AllowDirectoryListing bool               `json:"allowDirectoryListing" url:"allowDirectoryListing"`
-	AllowAnonymousRead    bool               `json:"allowAnonymousRead" url:"allowAnonymousRead"`
-	Policy                *string            `json:"policy,omitempty" url:"policy,omitempty"`
-	Acls                  []*S3BucketAcl     `json:"acls,omitempty" url:"acls,omitempty"`
+	Name                  string                             `json:"name" url:"name"`
+	Url                   string                             `json:"url" url:"url"`
+	Region                string                             `json:"region" url:"region"`
+	Status                ExternalBucketStatus               `json:"status" url:"status"`
+	DirectoryContents     []*S3ObjectDetails                 `json:"directoryContents,omitempty" url:"directoryContents,omitempty"`
+	AllowDirectoryListing bool                               `json:"allowDirectoryListing" url:"allowDirectoryListing"`
+	AllowAnonymousRead    bool                               `json:"allowAnonymousRead" url:"allowAnonymousRead"`
+	Policy                *string                            `json:"policy,omitempty" url:"policy,omitempty"`
+	Acls                  []*S3BucketAcl                     `json:"acls,omitempty" url:"acls,omitempty"`
+	AllowAclRead          bool                               `json:"allowAclRead" url:"allowAclRead"`
+	PolicyIsPublic        *bool                              `json:"policyIsPublic,omitempty" url:"policyIsPublic,omitempty"`
+	WriteCheck            *ExternalBucketWriteCheck          `json:"writeCheck,omitempty" url:"writeCheck,omitempty"`
+	AuthenticatedAccess   *ExternalBucketAuthenticatedAccess `json:"authenticatedAccess,omitempty" url:"authenticatedAccess,omitempty"`
 
 	extraProperties map[string]interface{}
 }
@@ -714,8 +1008,140 @@ func (e *ExternalBucket) String() string {
 	return fmt.Sprintf("%#v", e)
 }
 
+type ExternalBucketAccessCheck string
+
+const (
+	ExternalBucketAccessCheckList       ExternalBucketAccessCheck = "LIST"
+	ExternalBucketAccessCheckRead       ExternalBucketAccessCheck = "READ"
+	ExternalBucketAccessCheckAclRead    ExternalBucketAccessCheck = "ACL_READ"
+	ExternalBucketAccessCheckPolicyRead ExternalBucketAccessCheck = "POLICY_READ"
+)
+
+func NewExternalBucketAccessCheckFromString(s string) (ExternalBucketAccessCheck, error) {
+	switch s {
+	case "LIST":
+		return ExternalBucketAccessCheckList, nil
+	case "READ":
+		return ExternalBucketAccessCheckRead, nil
+	case "ACL_READ":
+		return ExternalBucketAccessCheckAclRead, nil
+	case "POLICY_READ":
+		return ExternalBucketAccessCheckPolicyRead, nil
+	}
+	var t ExternalBucketAccessCheck
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (e ExternalBucketAccessCheck) Ptr() *ExternalBucketAccessCheck {
+	return &e
+}
+
+// ExternalBucketAuthenticatedAccess records the results of repeating the anonymous checks with the caller's own
+// credentials, which are allowed for buckets that grant access to any authenticated AWS user.
+type ExternalBucketAuthenticatedAccess struct {
+	AllowDirectoryListing bool                        `json:"allowDirectoryListing" url:"allowDirectoryListing"`
+	AllowRead             bool                        `json:"allowRead" url:"allowRead"`
+	AllowAclRead          bool                        `json:"allowAclRead" url:"allowAclRead"`
+	AllowPolicyRead       bool                        `json:"allowPolicyRead" url:"allowPolicyRead"`
+	PolicyIsPublic        *bool                       `json:"policyIsPublic,omitempty" url:"policyIsPublic,omitempty"`
+	AuthenticatedOnly     []ExternalBucketAccessCheck `json:"authenticatedOnly" url:"authenticatedOnly"`
+
+	extraProperties map[string]interface{}
+}
+
+func (e *ExternalBucketAuthenticatedAccess) GetExtraProperties() map[string]interface{} {
+	return e.extraProperties
+}
+
+func (e *ExternalBucketAuthenticatedAccess) UnmarshalJSON(data []byte) error {
+	type unmarshaler ExternalBucketAuthenticatedAccess
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*e = ExternalBucketAuthenticatedAccess(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *e)
+	if err != nil {
+		return err
+	}
+	e.extraProperties = extraProperties
+
+	return nil
+}
+
+func (e *ExternalBucketAuthenticatedAccess) String() string {
+	if value, err := core.StringifyJSON(e); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", e)
+}
+
+type ExternalBucketStatus string
+
+const (
+	ExternalBucketStatusExists       ExternalBucketStatus = "EXISTS"
+	ExternalBucketStatusAccessDenied ExternalBucketStatus = "ACCESS_DENIED"
+)
+
+func NewExternalBucketStatusFromString(s string) (ExternalBucketStatus, error) {
+	switch s {
+	case "EXISTS":
+		return ExternalBucketStatusExists, nil
+	case "ACCESS_DENIED":
+		return ExternalBucketStatusAccessDenied, nil
+	}
+	var t ExternalBucketStatus
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (e ExternalBucketStatus) Ptr() *ExternalBucketStatus {
+	return &e
+}
+
+// ExternalBucketWriteCheck records the results of the opt-in anonymous write checks, which modify the bucket.
+// A canary object is uploaded and then deleted, and the bucket's current ACL is re-applied unchanged.
+type ExternalBucketWriteCheck struct {
+	CanaryKey           string `json:"canaryKey" url:"canaryKey"`
+	AllowAnonymousWrite *bool  `json:"allowAnonymousWrite,omitempty" url:"allowAnonymousWrite,omitempty"`
+	CanaryDeleted       bool   `json:"canaryDeleted" url:"canaryDeleted"`
+	AllowAclWrite       *bool  `json:"allowAclWrite,omitempty" url:"allowAclWrite,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (e *ExternalBucketWriteCheck) GetExtraProperties() map[string]interface{} {
+	return e.extraProperties
+}
+
+func (e *ExternalBucketWriteCheck) UnmarshalJSON(data []byte) error {
+	type unmarshaler ExternalBucketWriteCheck
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*e = ExternalBucketWriteCheck(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *e)
+	if err != nil {
+		return err
+	}
+	e.extraProperties = extraProperties
+
+	return nil
+}
+
+func (e *ExternalBucketWriteCheck) String() string {
+	if value, err := core.StringifyJSON(e); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", e)
+}
+
 type ExternalS3Report struct {
+	CallerAccountId *string           `json:"callerAccountId,omitempty" url:"callerAccountId,omitempty"`
 	ExternalBuckets []*ExternalBucket `json:"externalBuckets,omitempty" url:"externalBuckets,omitempty"`
+	NotFoundBuckets []string          `json:"notFoundBuckets,omitempty" url:"notFoundBuckets,omitempty"`
 	Errors          []string          `json:"errors,omitempty" url:"errors,omitempty"`
 
 	extraProperties map[string]interface{}
@@ -749,24 +1175,30 @@ func (e *ExternalS3Report) String() string {
 	return fmt.Sprintf("%#v", e)
 }
 
-type S3BucketAcl struct {
-	GranteeUri string `json:"granteeURI" url:"granteeURI"`
-	Permission string `json:"permission" url:"permission"`
+type S3AccessAnalysis struct {
+	Classification     S3AccessClassification `json:"classification" url:"classification"`
+	PublicRead         bool                   `json:"publicRead" url:"publicRead"`
+	PublicWrite        bool                   `json:"publicWrite" url:"publicWrite"`
+	CrossAccount       bool                   `json:"crossAccount" url:"crossAccount"`
+	PolicyUnknown      bool                   `json:"policyUnknown" url:"policyUnknown"`
+	AclUnknown         bool                   `json:"aclUnknown" url:"aclUnknown"`
+	ExternalPrincipals []*S3ExternalPrincipal `json:"externalPrincipals,omitempty" url:"externalPrincipals,omitempty"`
+	Statements         []*S3PolicyStatement   `json:"statements,omitempty" url:"statements,omitempty"`
 
 	extraProperties map[string]interface{}
 }
 
-func (s *S3BucketAcl) GetExtraProperties() map[string]interface{} {
+func (s *S3AccessAnalysis) GetExtraProperties() map[string]interface{} {
 	return s.extraProperties
 }
 
-func (s *S3BucketAcl) UnmarshalJSON(data []byte) error {
-	type unmarshaler S3BucketAcl
+func (s *S3AccessAnalysis) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3AccessAnalysis
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*s = S3BucketAcl(value)
+	*s = S3AccessAnalysis(value)
 
 	extraProperties, err := core.ExtractExtraProperties(data, *s)
 	if err != nil {
@@ -777,62 +1209,79 @@ func (s *S3BucketAcl) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (s *S3BucketAcl) String() string {
+func (s *S3AccessAnalysis) String() string {
 	if value, err := core.StringifyJSON(s); err == nil {
 		return value
 	}
 	return fmt.Sprintf("%#v", s)
 }
 
-type S3MfaDeleteStatus string
+type S3AccessClassification string
 
 const (
-	S3MfaDeleteStatusEnabled  S3MfaDeleteStatus = "Enabled"
-	S3MfaDeleteStatusDisabled S3MfaDeleteStatus = "Disabled"
+	S3AccessClassificationPrivate      S3AccessClassification = "PRIVATE"
+	S3AccessClassificationPublicRead   S3AccessClassification = "PUBLIC_READ"
+	S3AccessClassificationPublicWrite  S3AccessClassification = "PUBLIC_WRITE"
+	S3AccessClassificationCrossAccount S3AccessClassification = "CROSS_ACCOUNT"
+	S3AccessClassificationUnknown      S3AccessClassification = "UNKNOWN"
 )
 
-func NewS3MfaDeleteStatusFromString(s string) (S3MfaDeleteStatus, error) {
+func NewS3AccessClassificationFromString(s string) (S3AccessClassification, error) {
 	switch s {
-	case "Enabled":
-		return S3MfaDeleteStatusEnabled, nil
-	case "Disabled":
-		return S3MfaDeleteStatusDisabled, nil
-	}
-	var t S3MfaDeleteStatus
+	case "PRIVATE":
+		return S3AccessClassificationPrivate, nil
+	case "PUBLIC_READ":
+		return S3AccessClassificationPublicRead, nil
+	case "PUBLIC_WRITE":
+		return S3AccessClassificationPublicWrite, nil
+	case "CROSS_ACCOUNT":
+		return S3AccessClassificationCrossAccount, nil
+	case "UNKNOWN":
+		return S3AccessClassificationUnknown, nil
+	}
+	var t S3AccessClassification
 	return "", fmt.Errorf("%s is not a valid %T", s, t)
 }
 
-func (s S3MfaDeleteStatus) Ptr() *S3MfaDeleteStatus {
+func (s S3AccessClassification) Ptr() *S3AccessClassification {
 	return &s
 }
 
-type S3ObjectDetails struct {
-	Key          string     `json:"key" url:"key"`
-	LastModified *time.Time `json:"lastModified,omitempty" url:"lastModified,omitempty"`
-	Size         *int       `json:"size,omitempty" url:"size,omitempty"`
-	OwnerId      *string    `json:"ownerID,omitempty" url:"ownerID,omitempty"`
-	OwnerName    *string    `json:"ownerName,omitempty" url:"ownerName,omitempty"`
+type S3AccessPoint struct {
+	Name               string                            `json:"name" url:"name"`
+	Arn                string                            `json:"arn" url:"arn"`
+	Alias              *string                           `json:"alias,omitempty" url:"alias,omitempty"`
+	Region             string                            `json:"region" url:"region"`
+	Bucket             string                            `json:"bucket" url:"bucket"`
+	BucketAccountId    *string                           `json:"bucketAccountId,omitempty" url:"bucketAccountId,omitempty"`
+	NetworkOrigin      S3NetworkOrigin                   `json:"networkOrigin" url:"networkOrigin"`
+	VpcId              *string                           `json:"vpcId,omitempty" url:"vpcId,omitempty"`
+	CreationDate       *time.Time                        `json:"creationDate,omitempty" url:"creationDate,omitempty"`
+	PublicAccessConfig *S3PublicAccessBlockConfiguration `json:"publicAccessConfig,omitempty" url:"publicAccessConfig,omitempty"`
+	Policy             *string                           `json:"policy,omitempty" url:"policy,omitempty"`
+	PolicyIsPublic     *bool                             `json:"policyIsPublic,omitempty" url:"policyIsPublic,omitempty"`
+	Errors             []string                          `json:"errors,omitempty" url:"errors,omitempty"`
 
 	extraProperties map[string]interface{}
 }
 
-func (s *S3ObjectDetails) GetExtraProperties() map[string]interface{} {
+func (s *S3AccessPoint) GetExtraProperties() map[string]interface{} {
 	return s.extraProperties
 }
 
-func (s *S3ObjectDetails) UnmarshalJSON(data []byte) error {
-	type embed S3ObjectDetails
+func (s *S3AccessPoint) UnmarshalJSON(data []byte) error {
+	type embed S3AccessPoint
 	var unmarshaler = struct {
 		embed
-		LastModified *core.DateTime `json:"lastModified,omitempty"`
+		CreationDate *core.DateTime `json:"creationDate,omitempty"`
 	}{
 		embed: embed(*s),
 	}
 	if err := json.Unmarshal(data, &unmarshaler); err != nil {
 		return err
 	}
-	*s = S3ObjectDetails(unmarshaler.embed)
-	s.LastModified = unmarshaler.LastModified.TimePtr()
+	*s = S3AccessPoint(unmarshaler.embed)
+	s.CreationDate = unmarshaler.CreationDate.TimePtr()
 
 	extraProperties, err := core.ExtractExtraProperties(data, *s)
 	if err != nil {
@@ -843,7 +1292,551 @@ func (s *S3ObjectDetails) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (s *S3ObjectDetails) MarshalJSON() ([]byte, error) {
+func (s *S3AccessPoint) MarshalJSON() ([]byte, error) {
+	type embed S3AccessPoint
+	var marshaler = struct {
+		embed
+		CreationDate *core.DateTime `json:"creationDate,omitempty"`
+	}{
+		embed:        embed(*s),
+		CreationDate: core.NewOptionalDateTime(s.CreationDate),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (s *S3AccessPoint) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3AccessSource string
+
+const (
+	S3AccessSourcePolicy S3AccessSource = "POLICY"
+	S3AccessSourceAcl    S3AccessSource = "ACL"
+)
+
+func NewS3AccessSourceFromString(s string) (S3AccessSource, error) {
+	switch s {
+	case "POLICY":
+		return S3AccessSourcePolicy, nil
+	case "ACL":
+		return S3AccessSourceAcl, nil
+	}
+	var t S3AccessSource
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s S3AccessSource) Ptr() *S3AccessSource {
+	return &s
+}
+
+type S3BucketAcl struct {
+	GranteeUri string `json:"granteeURI" url:"granteeURI"`
+	Permission string `json:"permission" url:"permission"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3BucketAcl) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3BucketAcl) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3BucketAcl
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3BucketAcl(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3BucketAcl) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3BucketGrant struct {
+	GranteeType  S3GranteeType `json:"granteeType" url:"granteeType"`
+	GranteeId    *string       `json:"granteeID,omitempty" url:"granteeID,omitempty"`
+	GranteeName  *string       `json:"granteeName,omitempty" url:"granteeName,omitempty"`
+	GranteeEmail *string       `json:"granteeEmail,omitempty" url:"granteeEmail,omitempty"`
+	GranteeUri   *string       `json:"granteeURI,omitempty" url:"granteeURI,omitempty"`
+	Permission   string        `json:"permission" url:"permission"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3BucketGrant) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3BucketGrant) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3BucketGrant
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3BucketGrant(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3BucketGrant) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3CorsRule struct {
+	Id             *string  `json:"id,omitempty" url:"id,omitempty"`
+	AllowedOrigins []string `json:"allowedOrigins" url:"allowedOrigins"`
+	AllowedMethods []string `json:"allowedMethods" url:"allowedMethods"`
+	AllowedHeaders []string `json:"allowedHeaders,omitempty" url:"allowedHeaders,omitempty"`
+	ExposeHeaders  []string `json:"exposeHeaders,omitempty" url:"exposeHeaders,omitempty"`
+	MaxAgeSeconds  *int     `json:"maxAgeSeconds,omitempty" url:"maxAgeSeconds,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3CorsRule) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3CorsRule) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3CorsRule
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3CorsRule(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3CorsRule) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3ExternalPrincipal struct {
+	Principal                  string               `json:"principal" url:"principal"`
+	AccountId                  *string              `json:"accountId,omitempty" url:"accountId,omitempty"`
+	Source                     S3AccessSource       `json:"source" url:"source"`
+	Public                     bool                 `json:"public" url:"public"`
+	Read                       bool                 `json:"read" url:"read"`
+	Write                      bool                 `json:"write" url:"write"`
+	RestrictingConditions      []*S3PolicyCondition `json:"restrictingConditions,omitempty" url:"restrictingConditions,omitempty"`
+	BlockedByPublicAccessBlock bool                 `json:"blockedByPublicAccessBlock" url:"blockedByPublicAccessBlock"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3ExternalPrincipal) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3ExternalPrincipal) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3ExternalPrincipal
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3ExternalPrincipal(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3ExternalPrincipal) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3GranteeType string
+
+const (
+	S3GranteeTypeCanonicalUser         S3GranteeType = "CanonicalUser"
+	S3GranteeTypeAmazonCustomerByEmail S3GranteeType = "AmazonCustomerByEmail"
+	S3GranteeTypeGroup                 S3GranteeType = "Group"
+)
+
+func NewS3GranteeTypeFromString(s string) (S3GranteeType, error) {
+	switch s {
+	case "CanonicalUser":
+		return S3GranteeTypeCanonicalUser, nil
+	case "AmazonCustomerByEmail":
+		return S3GranteeTypeAmazonCustomerByEmail, nil
+	case "Group":
+		return S3GranteeTypeGroup, nil
+	}
+	var t S3GranteeType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s S3GranteeType) Ptr() *S3GranteeType {
+	return &s
+}
+
+type S3LifecycleRule struct {
+	Id                                 *string                  `json:"id,omitempty" url:"id,omitempty"`
+	Status                             string                   `json:"status" url:"status"`
+	Prefix                             *string                  `json:"prefix,omitempty" url:"prefix,omitempty"`
+	ExpirationDays                     *int                     `json:"expirationDays,omitempty" url:"expirationDays,omitempty"`
+	ExpirationDate                     *time.Time               `json:"expirationDate,omitempty" url:"expirationDate,omitempty"`
+	Transitions                        []*S3LifecycleTransition `json:"transitions,omitempty" url:"transitions,omitempty"`
+	NoncurrentVersionExpirationDays    *int                     `json:"noncurrentVersionExpirationDays,omitempty" url:"noncurrentVersionExpirationDays,omitempty"`
+	AbortIncompleteMultipartUploadDays *int                     `json:"abortIncompleteMultipartUploadDays,omitempty" url:"abortIncompleteMultipartUploadDays,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3LifecycleRule) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3LifecycleRule) UnmarshalJSON(data []byte) error {
+	type embed S3LifecycleRule
+	var unmarshaler = struct {
+		embed
+		ExpirationDate *core.DateTime `json:"expirationDate,omitempty"`
+	}{
+		embed: embed(*s),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*s = S3LifecycleRule(unmarshaler.embed)
+	s.ExpirationDate = unmarshaler.ExpirationDate.TimePtr()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3LifecycleRule) MarshalJSON() ([]byte, error) {
+	type embed S3LifecycleRule
+	var marshaler = struct {
+		embed
+		ExpirationDate *core.DateTime `json:"expirationDate,omitempty"`
+	}{
+		embed:          embed(*s),
+		ExpirationDate: core.NewOptionalDateTime(s.ExpirationDate),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (s *S3LifecycleRule) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3LifecycleTransition struct {
+	Days         *int       `json:"days,omitempty" url:"days,omitempty"`
+	Date         *time.Time `json:"date,omitempty" url:"date,omitempty"`
+	StorageClass string     `json:"storageClass" url:"storageClass"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3LifecycleTransition) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3LifecycleTransition) UnmarshalJSON(data []byte) error {
+	type embed S3LifecycleTransition
+	var unmarshaler = struct {
+		embed
+		Date *core.DateTime `json:"date,omitempty"`
+	}{
+		embed: embed(*s),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*s = S3LifecycleTransition(unmarshaler.embed)
+	s.Date = unmarshaler.Date.TimePtr()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3LifecycleTransition) MarshalJSON() ([]byte, error) {
+	type embed S3LifecycleTransition
+	var marshaler = struct {
+		embed
+		Date *core.DateTime `json:"date,omitempty"`
+	}{
+		embed: embed(*s),
+		Date:  core.NewOptionalDateTime(s.Date),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (s *S3LifecycleTransition) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3LoggingConfiguration struct {
+	TargetBucket string  `json:"targetBucket" url:"targetBucket"`
+	TargetPrefix *string `json:"targetPrefix,omitempty" url:"targetPrefix,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3LoggingConfiguration) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3LoggingConfiguration) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3LoggingConfiguration
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3LoggingConfiguration(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3LoggingConfiguration) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3MfaDeleteStatus string
+
+const (
+	S3MfaDeleteStatusEnabled  S3MfaDeleteStatus = "Enabled"
+	S3MfaDeleteStatusDisabled S3MfaDeleteStatus = "Disabled"
+)
+
+func NewS3MfaDeleteStatusFromString(s string) (S3MfaDeleteStatus, error) {
+	switch s {
+	case "Enabled":
+		return S3MfaDeleteStatusEnabled, nil
+	case "Disabled":
+		return S3MfaDeleteStatusDisabled, nil
+	}
+	var t S3MfaDeleteStatus
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s S3MfaDeleteStatus) Ptr() *S3MfaDeleteStatus {
+	return &s
+}
+
+type S3MultiRegionAccessPoint struct {
+	Name               string                            `json:"name" url:"name"`
+	Alias              *string                           `json:"alias,omitempty" url:"alias,omitempty"`
+	Status             string                            `json:"status" url:"status"`
+	CreatedAt          *time.Time                        `json:"createdAt,omitempty" url:"createdAt,omitempty"`
+	PublicAccessConfig *S3PublicAccessBlockConfiguration `json:"publicAccessConfig,omitempty" url:"publicAccessConfig,omitempty"`
+	Regions            []*S3MultiRegionAccessPointRegion `json:"regions,omitempty" url:"regions,omitempty"`
+	Policy             *string                           `json:"policy,omitempty" url:"policy,omitempty"`
+	Errors             []string                          `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3MultiRegionAccessPoint) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3MultiRegionAccessPoint) UnmarshalJSON(data []byte) error {
+	type embed S3MultiRegionAccessPoint
+	var unmarshaler = struct {
+		embed
+		CreatedAt *core.DateTime `json:"createdAt,omitempty"`
+	}{
+		embed: embed(*s),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*s = S3MultiRegionAccessPoint(unmarshaler.embed)
+	s.CreatedAt = unmarshaler.CreatedAt.TimePtr()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3MultiRegionAccessPoint) MarshalJSON() ([]byte, error) {
+	type embed S3MultiRegionAccessPoint
+	var marshaler = struct {
+		embed
+		CreatedAt *core.DateTime `json:"createdAt,omitempty"`
+	}{
+		embed:     embed(*s),
+		CreatedAt: core.NewOptionalDateTime(s.CreatedAt),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (s *S3MultiRegionAccessPoint) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3MultiRegionAccessPointRegion struct {
+	Bucket          string  `json:"bucket" url:"bucket"`
+	BucketAccountId *string `json:"bucketAccountId,omitempty" url:"bucketAccountId,omitempty"`
+	Region          string  `json:"region" url:"region"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3MultiRegionAccessPointRegion) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3MultiRegionAccessPointRegion) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3MultiRegionAccessPointRegion
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3MultiRegionAccessPointRegion(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3MultiRegionAccessPointRegion) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3NetworkOrigin string
+
+const (
+	S3NetworkOriginInternet S3NetworkOrigin = "Internet"
+	S3NetworkOriginVpc      S3NetworkOrigin = "VPC"
+)
+
+func NewS3NetworkOriginFromString(s string) (S3NetworkOrigin, error) {
+	switch s {
+	case "Internet":
+		return S3NetworkOriginInternet, nil
+	case "VPC":
+		return S3NetworkOriginVpc, nil
+	}
+	var t S3NetworkOrigin
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s S3NetworkOrigin) Ptr() *S3NetworkOrigin {
+	return &s
+}
+
+type S3ObjectDetails struct {
+	Key          string     `json:"key" url:"key"`
+	LastModified *time.Time `json:"lastModified,omitempty" url:"lastModified,omitempty"`
+	Size         *int       `json:"size,omitempty" url:"size,omitempty"`
+	OwnerId      *string    `json:"ownerID,omitempty" url:"ownerID,omitempty"`
+	OwnerName    *string    `json:"ownerName,omitempty" url:"ownerName,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3ObjectDetails) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3ObjectDetails) UnmarshalJSON(data []byte) error {
+	type embed S3ObjectDetails
+	var unmarshaler = struct {
+		embed
+		LastModified *core.DateTime `json:"lastModified,omitempty"`
+	}{
+		embed: embed(*s),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*s = S3ObjectDetails(unmarshaler.embed)
+	s.LastModified = unmarshaler.LastModified.TimePtr()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3ObjectDetails) MarshalJSON() ([]byte, error) {
 	type embed S3ObjectDetails
 	var marshaler = struct {
 		embed
@@ -862,6 +1855,168 @@ func (s *S3ObjectDetails) String() string {
 	return fmt.Sprintf("%#v", s)
 }
 
+type S3ObjectLockConfiguration struct {
+	Enabled bool                       `json:"enabled" url:"enabled"`
+	Mode    *S3ObjectLockRetentionMode `json:"mode,omitempty" url:"mode,omitempty"`
+	Days    *int                       `json:"days,omitempty" url:"days,omitempty"`
+	Years   *int                       `json:"years,omitempty" url:"years,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3ObjectLockConfiguration) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3ObjectLockConfiguration) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3ObjectLockConfiguration
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3ObjectLockConfiguration(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3ObjectLockConfiguration) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3ObjectLockRetentionMode string
+
+const (
+	S3ObjectLockRetentionModeGovernance S3ObjectLockRetentionMode = "GOVERNANCE"
+	S3ObjectLockRetentionModeCompliance S3ObjectLockRetentionMode = "COMPLIANCE"
+)
+
+func NewS3ObjectLockRetentionModeFromString(s string) (S3ObjectLockRetentionMode, error) {
+	switch s {
+	case "GOVERNANCE":
+		return S3ObjectLockRetentionModeGovernance, nil
+	case "COMPLIANCE":
+		return S3ObjectLockRetentionModeCompliance, nil
+	}
+	var t S3ObjectLockRetentionMode
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s S3ObjectLockRetentionMode) Ptr() *S3ObjectLockRetentionMode {
+	return &s
+}
+
+type S3ObjectOwnership string
+
+const (
+	S3ObjectOwnershipBucketOwnerPreferred S3ObjectOwnership = "BucketOwnerPreferred"
+	S3ObjectOwnershipObjectWriter         S3ObjectOwnership = "ObjectWriter"
+	S3ObjectOwnershipBucketOwnerEnforced  S3ObjectOwnership = "BucketOwnerEnforced"
+)
+
+func NewS3ObjectOwnershipFromString(s string) (S3ObjectOwnership, error) {
+	switch s {
+	case "BucketOwnerPreferred":
+		return S3ObjectOwnershipBucketOwnerPreferred, nil
+	case "ObjectWriter":
+		return S3ObjectOwnershipObjectWriter, nil
+	case "BucketOwnerEnforced":
+		return S3ObjectOwnershipBucketOwnerEnforced, nil
+	}
+	var t S3ObjectOwnership
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s S3ObjectOwnership) Ptr() *S3ObjectOwnership {
+	return &s
+}
+
+type S3PolicyCondition struct {
+	Operator string   `json:"operator" url:"operator"`
+	Key      string   `json:"key" url:"key"`
+	Values   []string `json:"values" url:"values"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3PolicyCondition) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3PolicyCondition) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3PolicyCondition
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3PolicyCondition(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3PolicyCondition) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3PolicyStatement struct {
+	Sid           *string              `json:"sid,omitempty" url:"sid,omitempty"`
+	Effect        string               `json:"effect" url:"effect"`
+	Principals    []string             `json:"principals,omitempty" url:"principals,omitempty"`
+	NotPrincipals []string             `json:"notPrincipals,omitempty" url:"notPrincipals,omitempty"`
+	Actions       []string             `json:"actions,omitempty" url:"actions,omitempty"`
+	NotActions    []string             `json:"notActions,omitempty" url:"notActions,omitempty"`
+	Resources     []string             `json:"resources,omitempty" url:"resources,omitempty"`
+	NotResources  []string             `json:"notResources,omitempty" url:"notResources,omitempty"`
+	Conditions    []*S3PolicyCondition `json:"conditions,omitempty" url:"conditions,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3PolicyStatement) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3PolicyStatement) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3PolicyStatement
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3PolicyStatement(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3PolicyStatement) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
 type S3PublicAccessBlockConfiguration struct {
 	BlockPublicAcls       bool `json:"blockPublicAcls" url:"blockPublicAcls"`
 	IgnorePublicAcls      bool `json:"ignorePublicAcls" url:"ignorePublicAcls"`
@@ -899,10 +2054,88 @@ func (s *S3PublicAccessBlockConfiguration) String() string {
 	return fmt.Sprintf("%#v", s)
 }
 
+type S3ReplicationConfiguration struct {
+	Role  string               `json:"role" url:"role"`
+	Rules []*S3ReplicationRule `json:"rules,omitempty" url:"rules,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3ReplicationConfiguration) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3ReplicationConfiguration) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3ReplicationConfiguration
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3ReplicationConfiguration(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3ReplicationConfiguration) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
+type S3ReplicationRule struct {
+	Id                 *string `json:"id,omitempty" url:"id,omitempty"`
+	Status             string  `json:"status" url:"status"`
+	Priority           *int    `json:"priority,omitempty" url:"priority,omitempty"`
+	Prefix             *string `json:"prefix,omitempty" url:"prefix,omitempty"`
+	DestinationBucket  string  `json:"destinationBucket" url:"destinationBucket"`
+	DestinationAccount *string `json:"destinationAccount,omitempty" url:"destinationAccount,omitempty"`
+	StorageClass       *string `json:"storageClass,omitempty" url:"storageClass,omitempty"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3ReplicationRule) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3ReplicationRule) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3ReplicationRule
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3ReplicationRule(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3ReplicationRule) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
 type S3Report struct {
-	AccountId string    `json:"accountId" url:"accountId"`
-	S3Buckets []*Bucket `json:"s3Buckets,omitempty" url:"s3Buckets,omitempty"`
-	Errors    []string  `json:"errors,omitempty" url:"errors,omitempty"`
+	AccountId                 string                            `json:"accountId" url:"accountId"`
+	AccountPublicAccessConfig *S3PublicAccessBlockConfiguration `json:"accountPublicAccessConfig,omitempty" url:"accountPublicAccessConfig,omitempty"`
+	S3Buckets                 []*Bucket                         `json:"s3Buckets,omitempty" url:"s3Buckets,omitempty"`
+	AccessPoints              []*S3AccessPoint                  `json:"accessPoints,omitempty" url:"accessPoints,omitempty"`
+	MultiRegionAccessPoints   []*S3MultiRegionAccessPoint       `json:"multiRegionAccessPoints,omitempty" url:"multiRegionAccessPoints,omitempty"`
+	Errors                    []string                          `json:"errors,omitempty" url:"errors,omitempty"`
 
 	extraProperties map[string]interface{}
 }
@@ -960,6 +2193,43 @@ func (s S3ServerSideEncryption) Ptr() *S3ServerSideEncryption {
 	return &s
 }
 
+type S3WebsiteConfiguration struct {
+	IndexDocument         *string `json:"indexDocument,omitempty" url:"indexDocument,omitempty"`
+	ErrorDocument         *string `json:"errorDocument,omitempty" url:"errorDocument,omitempty"`
+	RedirectAllRequestsTo *string `json:"redirectAllRequestsTo,omitempty" url:"redirectAllRequestsTo,omitempty"`
+	RoutingRuleCount      int     `json:"routingRuleCount" url:"routingRuleCount"`
+
+	extraProperties map[string]interface{}
+}
+
+func (s *S3WebsiteConfiguration) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *S3WebsiteConfiguration) UnmarshalJSON(data []byte) error {
+	type unmarshaler S3WebsiteConfiguration
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = S3WebsiteConfiguration(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	return nil
+}
+
+func (s *S3WebsiteConfiguration) String() string {
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
 type ActionInfo struct {
 	Type       ActionType `json:"type" url:"type"`
 	JsonString *string    `json:"jsonString,omitempty" url:"jsonString,omitempty"`
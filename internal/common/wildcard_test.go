@@ -0,0 +1,36 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		desc        string
+		givePattern string
+		giveValue   string
+		want        bool
+	}{
+		{desc: "exact", givePattern: "s3:GetObject", giveValue: "s3:GetObject", want: true},
+		{desc: "case sensitive", givePattern: "s3:getobject", giveValue: "s3:GetObject", want: false},
+		{desc: "star", givePattern: "*", giveValue: "anything", want: true},
+		{desc: "star matches empty", givePattern: "s3:Get*", giveValue: "s3:Get", want: true},
+		{desc: "prefix star", givePattern: "s3:Get*", giveValue: "s3:GetObjectAcl", want: true},
+		{desc: "prefix mismatch", givePattern: "s3:Get*", giveValue: "s3:PutObject", want: false},
+		{desc: "inner star", givePattern: "arn:aws:s3:::bucket/*/key", giveValue: "arn:aws:s3:::bucket/a/b/key", want: true},
+		{desc: "inner star backtracks", givePattern: "a*b*c", giveValue: "aXbYbZc", want: true},
+		{desc: "inner star mismatch", givePattern: "a*b*c", giveValue: "aXbYbZ", want: false},
+		{desc: "question mark", givePattern: "s3:?etObject", giveValue: "s3:GetObject", want: true},
+		{desc: "question mark needs a character", givePattern: "s3:GetObject?", giveValue: "s3:GetObject", want: false},
+		{desc: "trailing stars", givePattern: "abc**", giveValue: "abc", want: true},
+		{desc: "empty pattern", givePattern: "", giveValue: "a", want: false},
+		{desc: "empty pattern and value", givePattern: "", giveValue: "", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.want, WildcardMatch(tt.givePattern, tt.giveValue))
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package ec2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Method-Security/methodaws/internal/sts"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+const (
+	resourceTypeInstance         = "ec2_instance"
+	resourceTypeLoadBalancer     = "load_balancer"
+	resourceTypeNATGateway       = "nat_gateway"
+	resourceTypeNetworkInterface = "network_interface"
+	resourceTypeUnassociated     = "unassociated"
+)
+
+// publicIPIndex merges the public IPs reported by the different EC2 APIs, keyed by address.
+type publicIPIndex struct {
+	region    string
+	order     []string
+	addresses map[string]*PublicIP
+}
+
+// add records an address, merging it with any previous sighting. A more specific resource replaces a bare network
+// interface, unassociated or unidentified mapping, and an address is attached if any source reports it as attached.
+func (i *publicIPIndex) add(ip PublicIP, source string) {
+	existing, ok := i.addresses[ip.Address]
+	if !ok {
+		ip.Region = i.region
+		ip.Sources = []string{source}
+		i.addresses[ip.Address] = &ip
+		i.order = append(i.order, ip.Address)
+		return
+	}
+
+	existing.Sources = appendUnique(existing.Sources, source)
+	existing.Attached = existing.Attached || ip.Attached
+	moreSpecific := existing.ResourceType == resourceTypeNetworkInterface && ip.ResourceType != resourceTypeUnassociated
+	if existing.ResourceType == resourceTypeUnassociated || moreSpecific || (existing.ResourceID == "" && ip.ResourceID != "") {
+		existing.ResourceType = ip.ResourceType
+		existing.ResourceID = ip.ResourceID
+	}
+	if existing.NetworkInterfaceID == "" {
+		existing.NetworkInterfaceID = ip.NetworkInterfaceID
+	}
+	if existing.AllocationID == "" {
+		existing.AllocationID = ip.AllocationID
+	}
+}
+
+func (i *publicIPIndex) list() []PublicIP {
+	ips := []PublicIP{}
+	for _, address := range i.order {
+		ips = append(ips, *i.addresses[address])
+	}
+	return ips
+}
+
+// EnumeratePublicIPsForRegion combines the Elastic IPs, instance public IPs, network interface association IPs and
+// IPv6 addresses, and NAT gateway IPs of a region into a single list of public IPs, each mapped to the resource that
+// uses it. Load balancer addresses are discovered through the network interfaces that AWS creates for them.
+func EnumeratePublicIPsForRegion(ctx context.Context, cfg aws.Config, region string) ([]PublicIP, []string) {
+	cfg.Region = region
+	svc := ec2.NewFromConfig(cfg)
+	index := &publicIPIndex{region: region, addresses: map[string]*PublicIP{}}
+	var errors []string
+
+	addresses, err := svc.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Error in region %s: %v", region, err))
+	} else {
+		for _, address := range addresses.Addresses {
+			ip := PublicIP{
+				Address:            aws.ToString(address.PublicIp),
+				Version:            "ipv4",
+				ResourceType:       resourceTypeUnassociated,
+				NetworkInterfaceID: aws.ToString(address.NetworkInterfaceId),
+				AllocationID:       aws.ToString(address.AllocationId),
+				Attached:           address.AssociationId != nil || address.InstanceId != nil,
+			}
+			if address.InstanceId != nil {
+				ip.ResourceType = resourceTypeInstance
+				ip.ResourceID = *address.InstanceId
+			} else if address.NetworkInterfaceId != nil {
+				ip.ResourceType = resourceTypeNetworkInterface
+				ip.ResourceID = *address.NetworkInterfaceId
+			}
+			index.add(ip, "elastic_ip")
+		}
+	}
+
+	instances, err := EnumerateEc2ForRegion(ctx, cfg, region)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Error in region %s: %v", region, err))
+	} else {
+		for _, instance := range instances.Resources.EC2Instances {
+			if instance.Instance.PublicIpAddress == nil {
+				continue
+			}
+			index.add(PublicIP{
+				Address:      *instance.Instance.PublicIpAddress,
+				Version:      "ipv4",
+				ResourceType: resourceTypeInstance,
+				ResourceID:   aws.ToString(instance.Instance.InstanceId),
+				Attached:     true,
+			}, "instance")
+		}
+	}
+
+	networkInterfaces, err := describeNetworkInterfaces(ctx, svc, nil)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Error in region %s: %v", region, err))
+	}
+	for _, eni := range networkInterfaces {
+		resourceType, resourceID := resourceForNetworkInterface(eni)
+		if eni.Association != nil && eni.Association.PublicIp != nil {
+			index.add(PublicIP{
+				Address:            *eni.Association.PublicIp,
+				Version:            "ipv4",
+				ResourceType:       resourceType,
+				ResourceID:         resourceID,
+				NetworkInterfaceID: aws.ToString(eni.NetworkInterfaceId),
+				AllocationID:       aws.ToString(eni.Association.AllocationId),
+				Attached:           true,
+			}, "network_interface")
+		}
+		for _, ipv6 := range eni.Ipv6Addresses {
+			index.add(PublicIP{
+				Address:            aws.ToString(ipv6.Ipv6Address),
+				Version:            "ipv6",
+				ResourceType:       resourceType,
+				ResourceID:         resourceID,
+				NetworkInterfaceID: aws.ToString(eni.NetworkInterfaceId),
+				Attached:           true,
+			}, "network_interface")
+		}
+	}
+
+	paginator := ec2.NewDescribeNatGatewaysPaginator(svc, &ec2.DescribeNatGatewaysInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Error in region %s: %v", region, err))
+			break
+		}
+		for _, natGateway := range output.NatGateways {
+			for _, address := range natGateway.NatGatewayAddresses {
+				if address.PublicIp == nil {
+					continue
+				}
+				index.add(PublicIP{
+					Address:            *address.PublicIp,
+					Version:            "ipv4",
+					ResourceType:       resourceTypeNATGateway,
+					ResourceID:         aws.ToString(natGateway.NatGatewayId),
+					NetworkInterfaceID: aws.ToString(address.NetworkInterfaceId),
+					AllocationID:       aws.ToString(address.AllocationId),
+					Attached:           natGateway.State == types.NatGatewayStateAvailable,
+				}, "nat_gateway")
+			}
+		}
+	}
+
+	return index.list(), errors
+}
+
+// EnumeratePublicIPs lists every public IP address owned by the account across multiple regions alongside any
+// non-fatal errors that occurred during the execution of the `methodaws ec2 public-ips` subcommand.
+func EnumeratePublicIPs(ctx context.Context, cfg aws.Config, regions []string) (*PublicIPReport, error) {
+	accountID, err := sts.GetAccountID(ctx, cfg)
+	if err != nil {
+		return &PublicIPReport{
+			PublicIPs: []PublicIP{},
+			Errors:    []string{err.Error()},
+		}, err
+	}
+
+	report := PublicIPReport{
+		AccountID: aws.ToString(accountID),
+		PublicIPs: []PublicIP{},
+		Errors:    []string{},
+	}
+
+	for _, region := range regions {
+		ips, errors := EnumeratePublicIPsForRegion(ctx, cfg, region)
+		report.PublicIPs = append(report.PublicIPs, ips...)
+		report.Errors = append(report.Errors, errors...)
+	}
+
+	return &report, nil
+}
+
+// resourceForNetworkInterface returns the type and ID of the resource that a network interface belongs to.
+func resourceForNetworkInterface(eni types.NetworkInterface) (string, string) {
+	if eni.Attachment != nil && eni.Attachment.InstanceId != nil {
+		return resourceTypeInstance, *eni.Attachment.InstanceId
+	}
+	if loadBalancer, ok := loadBalancerFromDescription(aws.ToString(eni.Description)); ok {
+		return resourceTypeLoadBalancer, loadBalancer
+	}
+	if eni.InterfaceType == types.NetworkInterfaceTypeNatGateway {
+		return resourceTypeNATGateway, ""
+	}
+	return resourceTypeNetworkInterface, aws.ToString(eni.NetworkInterfaceId)
+}
@@ -25,10 +25,109 @@ type ResourceReport struct {
 }
 
 // SecurityGroupReport contains the security groups and any errors that occurred during the execution of the
-// `methodaws securitygroup enumerate` subcommand.
+// `methodaws securitygroup enumerate` subcommand. Analysis is only populated when the analysis mode is requested.
 // Non-fatal errors are stored in the Errors field.
 type SecurityGroupReport struct {
-	AccountID      string                `json:"account_id" yaml:"account_id"`
-	SecurityGroups []types.SecurityGroup `json:"security_groups" yaml:"security_groups"`
-	Errors         []string              `json:"errors" yaml:"errors"`
+	AccountID      string                  `json:"account_id" yaml:"account_id"`
+	SecurityGroups []types.SecurityGroup   `json:"security_groups" yaml:"security_groups"`
+	Analysis       []SecurityGroupAnalysis `json:"analysis,omitempty" yaml:"analysis,omitempty"`
+	Errors         []string                `json:"errors" yaml:"errors"`
+}
+
+// SecurityGroupRule is a single normalized security group rule. Every AWS IpPermission is flattened into one rule per
+// source or destination so that exactly one of CIDR, IPv6CIDR, PrefixListID or ReferencedGroupID is set.
+type SecurityGroupRule struct {
+	Direction              string  `json:"direction" yaml:"direction"`
+	Protocol               string  `json:"protocol" yaml:"protocol"`
+	FromPort               *int32  `json:"from_port,omitempty" yaml:"from_port,omitempty"`
+	ToPort                 *int32  `json:"to_port,omitempty" yaml:"to_port,omitempty"`
+	CIDR                   *string `json:"cidr,omitempty" yaml:"cidr,omitempty"`
+	IPv6CIDR               *string `json:"ipv6_cidr,omitempty" yaml:"ipv6_cidr,omitempty"`
+	PrefixListID           *string `json:"prefix_list_id,omitempty" yaml:"prefix_list_id,omitempty"`
+	ReferencedGroupID      *string `json:"referenced_group_id,omitempty" yaml:"referenced_group_id,omitempty"`
+	ReferencedGroupOwnerID *string `json:"referenced_group_owner_id,omitempty" yaml:"referenced_group_owner_id,omitempty"`
+	Description            *string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// SecurityGroupExposure flags an ingress rule that opens a sensitive port to the entire internet.
+type SecurityGroupExposure struct {
+	Port     int32  `json:"port" yaml:"port"`
+	Service  string `json:"service" yaml:"service"`
+	Protocol string `json:"protocol" yaml:"protocol"`
+	Source   string `json:"source" yaml:"source"`
+}
+
+// SecurityGroupAttachments contains the resources that a security group is attached to.
+type SecurityGroupAttachments struct {
+	NetworkInterfaceIDs []string `json:"network_interface_ids" yaml:"network_interface_ids"`
+	InstanceIDs         []string `json:"instance_ids" yaml:"instance_ids"`
+	LoadBalancers       []string `json:"load_balancers" yaml:"load_balancers"`
+	RDSInstanceIDs      []string `json:"rds_instance_ids" yaml:"rds_instance_ids"`
+}
+
+// SecurityGroupAnalysis contains the normalized rules, internet exposures and attachments of a single security group.
+// AttachmentsUnknown is set when the network interfaces or RDS instances of the group's region could not be listed, in
+// which case Attachments may be incomplete.
+type SecurityGroupAnalysis struct {
+	GroupID            string                   `json:"group_id" yaml:"group_id"`
+	GroupName          string                   `json:"group_name" yaml:"group_name"`
+	VPCID              string                   `json:"vpc_id" yaml:"vpc_id"`
+	Region             string                   `json:"region" yaml:"region"`
+	Rules              []SecurityGroupRule      `json:"rules" yaml:"rules"`
+	Exposures          []SecurityGroupExposure  `json:"exposures" yaml:"exposures"`
+	Attachments        SecurityGroupAttachments `json:"attachments" yaml:"attachments"`
+	AttachmentsUnknown bool                     `json:"attachments_unknown" yaml:"attachments_unknown"`
+}
+
+// UnusedSecurityGroup is a security group that is not attached to any network interface. ReferencedBy lists the
+// groups whose rules reference it, and ReferencedOnlyByUnused is true when every one of those groups is itself unused.
+type UnusedSecurityGroup struct {
+	GroupID                string   `json:"group_id" yaml:"group_id"`
+	GroupName              string   `json:"group_name" yaml:"group_name"`
+	VPCID                  string   `json:"vpc_id" yaml:"vpc_id"`
+	Region                 string   `json:"region" yaml:"region"`
+	ReferencedBy           []string `json:"referenced_by" yaml:"referenced_by"`
+	ReferencedOnlyByUnused bool     `json:"referenced_only_by_unused" yaml:"referenced_only_by_unused"`
+}
+
+// PermissiveDefaultGroup is a default VPC security group that still has rules. Default groups cannot be deleted, so
+// they should instead be emptied of all ingress and egress rules.
+type PermissiveDefaultGroup struct {
+	GroupID string              `json:"group_id" yaml:"group_id"`
+	VPCID   string              `json:"vpc_id" yaml:"vpc_id"`
+	Region  string              `json:"region" yaml:"region"`
+	Rules   []SecurityGroupRule `json:"rules" yaml:"rules"`
+}
+
+// UnusedSecurityGroupReport contains the unused security groups, the permissive default groups and any errors that
+// occurred during the execution of the `methodaws securitygroup unused` subcommand.
+// Non-fatal errors are stored in the Errors field.
+type UnusedSecurityGroupReport struct {
+	AccountID               string                   `json:"account_id" yaml:"account_id"`
+	UnusedSecurityGroups    []UnusedSecurityGroup    `json:"unused_security_groups" yaml:"unused_security_groups"`
+	PermissiveDefaultGroups []PermissiveDefaultGroup `json:"permissive_default_groups" yaml:"permissive_default_groups"`
+	Errors                  []string                 `json:"errors" yaml:"errors"`
+}
+
+// PublicIP is a public IP address owned by the account alongside the resource it maps to. Sources lists every API
+// that reported the address, and Attached is false for addresses, such as unassociated Elastic IPs, that are
+// allocated but not currently in use.
+type PublicIP struct {
+	Address            string   `json:"address" yaml:"address"`
+	Version            string   `json:"version" yaml:"version"`
+	ResourceType       string   `json:"resource_type" yaml:"resource_type"`
+	ResourceID         string   `json:"resource_id,omitempty" yaml:"resource_id,omitempty"`
+	NetworkInterfaceID string   `json:"network_interface_id,omitempty" yaml:"network_interface_id,omitempty"`
+	AllocationID       string   `json:"allocation_id,omitempty" yaml:"allocation_id,omitempty"`
+	Attached           bool     `json:"attached" yaml:"attached"`
+	Sources            []string `json:"sources" yaml:"sources"`
+	Region             string   `json:"region" yaml:"region"`
+}
+
+// PublicIPReport contains the public IP addresses and any errors that occurred during the execution of the
+// `methodaws ec2 public-ips` subcommand. Non-fatal errors are stored in the Errors field.
+type PublicIPReport struct {
+	AccountID string     `json:"account_id" yaml:"account_id"`
+	PublicIPs []PublicIP `json:"public_ips" yaml:"public_ips"`
+	Errors    []string   `json:"errors" yaml:"errors"`
 }
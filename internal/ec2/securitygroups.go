@@ -12,10 +12,12 @@ import (
 
 // EnumerateSecurityGroups lists all of the security groups available to the caller across multiple regions
 // alongside any non-fatal errors that occurred during the execution of the `methodaws securitygroup enumerate` subcommand.
-// If vpcID is not nil, it will only return security groups associated with that VPC.
-func EnumerateSecurityGroups(ctx context.Context, cfg aws.Config, vpcID *string, regions []string) (SecurityGroupReport, error) {
+// If vpcID is not nil, it will only return security groups associated with that VPC. If analyze is true, the report
+// also contains the normalized rules, internet exposures and attachments of every security group.
+func EnumerateSecurityGroups(ctx context.Context, cfg aws.Config, vpcID *string, regions []string, analyze bool) (SecurityGroupReport, error) {
 	var accountID string
 	var allSecurityGroups []types.SecurityGroup
+	var allAnalysis []SecurityGroupAnalysis
 	var allErrors []string
 
 	id, err := sts.GetAccountID(ctx, cfg)
@@ -37,11 +39,18 @@ func EnumerateSecurityGroups(ctx context.Context, cfg aws.Config, vpcID *string,
 		securityGroups, errors := EnumerateSecurityGroupForRegion(ctx, cfg, vpcID, region)
 		allSecurityGroups = append(allSecurityGroups, securityGroups...)
 		allErrors = append(allErrors, errors...)
+
+		if analyze {
+			analysis, errors := AnalyzeSecurityGroupsForRegion(ctx, cfg, securityGroups, vpcID, region)
+			allAnalysis = append(allAnalysis, analysis...)
+			allErrors = append(allErrors, errors...)
+		}
 	}
 
 	return SecurityGroupReport{
 		AccountID:      accountID,
 		SecurityGroups: allSecurityGroups,
+		Analysis:       allAnalysis,
 		Errors:         allErrors,
 	}, nil
 }
@@ -0,0 +1,255 @@
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Method-Security/methodaws/internal/rds"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// sensitivePorts lists the ports that should never be exposed to the internet alongside the service that typically
+// listens on them.
+var sensitivePorts = []struct {
+	Port    int32
+	Service string
+}{
+	{22, "SSH"},
+	{3389, "RDP"},
+	{3306, "MySQL"},
+	{5432, "PostgreSQL"},
+	{6379, "Redis"},
+	{9200, "Elasticsearch"},
+	{27017, "MongoDB"},
+}
+
+const (
+	anyIPv4 = "0.0.0.0/0"
+	anyIPv6 = "::/0"
+)
+
+// AnalyzeSecurityGroupsForRegion flattens the rules of the provided security groups, flags internet exposure on
+// sensitive ports, and resolves the network interfaces, instances, load balancers and RDS instances that each group is
+// attached to. The security groups must all belong to the provided region. When the network interfaces or RDS
+// instances cannot be listed, every group's attachments are marked unknown so that attached groups do not look
+// unattached.
+func AnalyzeSecurityGroupsForRegion(ctx context.Context, cfg aws.Config, securityGroups []types.SecurityGroup, vpcID *string, region string) ([]SecurityGroupAnalysis, []string) {
+	cfg.Region = region
+	svc := ec2.NewFromConfig(cfg)
+	var errors []string
+
+	attachments := map[string]*SecurityGroupAttachments{}
+	for _, sg := range securityGroups {
+		attachments[aws.ToString(sg.GroupId)] = &SecurityGroupAttachments{
+			NetworkInterfaceIDs: []string{},
+			InstanceIDs:         []string{},
+			LoadBalancers:       []string{},
+			RDSInstanceIDs:      []string{},
+		}
+	}
+
+	attachmentsUnknown := false
+	networkInterfaces, err := describeNetworkInterfaces(ctx, svc, vpcID)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Error in region %s: %v", region, err))
+		attachmentsUnknown = true
+	}
+	for _, eni := range networkInterfaces {
+		for _, group := range eni.Groups {
+			attachment, ok := attachments[aws.ToString(group.GroupId)]
+			if !ok {
+				continue
+			}
+			attachment.NetworkInterfaceIDs = append(attachment.NetworkInterfaceIDs, aws.ToString(eni.NetworkInterfaceId))
+			if eni.Attachment != nil && eni.Attachment.InstanceId != nil {
+				attachment.InstanceIDs = appendUnique(attachment.InstanceIDs, *eni.Attachment.InstanceId)
+			}
+			if loadBalancer, ok := loadBalancerFromDescription(aws.ToString(eni.Description)); ok {
+				attachment.LoadBalancers = appendUnique(attachment.LoadBalancers, loadBalancer)
+			}
+		}
+	}
+
+	rdsReport, err := rds.EnumerateRdsForRegion(ctx, cfg, region)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Error in region %s: %v", region, err))
+		attachmentsUnknown = true
+	} else {
+		for _, e := range rdsReport.Errors {
+			errors = append(errors, fmt.Sprintf("Error in region %s: %s", region, e))
+		}
+		attachmentsUnknown = attachmentsUnknown || len(rdsReport.Errors) > 0
+		for _, dbInstance := range rdsReport.Resources.RDSInstances {
+			for _, group := range dbInstance.VpcSecurityGroups {
+				if attachment, ok := attachments[aws.ToString(group.VpcSecurityGroupId)]; ok {
+					attachment.RDSInstanceIDs = append(attachment.RDSInstanceIDs, aws.ToString(dbInstance.DBInstanceIdentifier))
+				}
+			}
+		}
+	}
+
+	analysis := []SecurityGroupAnalysis{}
+	for _, sg := range securityGroups {
+		rules := NormalizeSecurityGroupRules(sg)
+		analysis = append(analysis, SecurityGroupAnalysis{
+			GroupID:            aws.ToString(sg.GroupId),
+			GroupName:          aws.ToString(sg.GroupName),
+			VPCID:              aws.ToString(sg.VpcId),
+			Region:             region,
+			Rules:              rules,
+			Exposures:          findExposures(rules),
+			Attachments:        *attachments[aws.ToString(sg.GroupId)],
+			AttachmentsUnknown: attachmentsUnknown,
+		})
+	}
+
+	return analysis, errors
+}
+
+// NormalizeSecurityGroupRules flattens the ingress and egress permissions of a security group into one rule per
+// CIDR, IPv6 CIDR, prefix list or referenced security group.
+func NormalizeSecurityGroupRules(sg types.SecurityGroup) []SecurityGroupRule {
+	rules := []SecurityGroupRule{}
+	rules = append(rules, normalizePermissions("ingress", sg.IpPermissions)...)
+	rules = append(rules, normalizePermissions("egress", sg.IpPermissionsEgress)...)
+	return rules
+}
+
+func normalizePermissions(direction string, permissions []types.IpPermission) []SecurityGroupRule {
+	rules := []SecurityGroupRule{}
+	for _, permission := range permissions {
+		base := SecurityGroupRule{
+			Direction: direction,
+			Protocol:  aws.ToString(permission.IpProtocol),
+			FromPort:  permission.FromPort,
+			ToPort:    permission.ToPort,
+		}
+
+		for _, ipRange := range permission.IpRanges {
+			rule := base
+			rule.CIDR = ipRange.CidrIp
+			rule.Description = ipRange.Description
+			rules = append(rules, rule)
+		}
+		for _, ipv6Range := range permission.Ipv6Ranges {
+			rule := base
+			rule.IPv6CIDR = ipv6Range.CidrIpv6
+			rule.Description = ipv6Range.Description
+			rules = append(rules, rule)
+		}
+		for _, prefixList := range permission.PrefixListIds {
+			rule := base
+			rule.PrefixListID = prefixList.PrefixListId
+			rule.Description = prefixList.Description
+			rules = append(rules, rule)
+		}
+		for _, pair := range permission.UserIdGroupPairs {
+			rule := base
+			rule.ReferencedGroupID = pair.GroupId
+			rule.ReferencedGroupOwnerID = pair.UserId
+			rule.Description = pair.Description
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// findExposures returns an exposure for every sensitive port that an ingress rule opens to 0.0.0.0/0 or ::/0.
+func findExposures(rules []SecurityGroupRule) []SecurityGroupExposure {
+	exposures := []SecurityGroupExposure{}
+	for _, rule := range rules {
+		if rule.Direction != "ingress" {
+			continue
+		}
+		var source string
+		switch {
+		case aws.ToString(rule.CIDR) == anyIPv4:
+			source = anyIPv4
+		case aws.ToString(rule.IPv6CIDR) == anyIPv6:
+			source = anyIPv6
+		default:
+			continue
+		}
+
+		for _, sensitive := range sensitivePorts {
+			if RuleAllowsPort(rule, "tcp", sensitive.Port) {
+				exposures = append(exposures, SecurityGroupExposure{
+					Port:     sensitive.Port,
+					Service:  sensitive.Service,
+					Protocol: rule.Protocol,
+					Source:   source,
+				})
+			}
+		}
+	}
+	return exposures
+}
+
+// RuleAllowsPort reports whether a normalized rule allows traffic on the given protocol and port. A protocol of "-1"
+// allows all traffic, and a rule with no port range covers every port of its protocol.
+func RuleAllowsPort(rule SecurityGroupRule, protocol string, port int32) bool {
+	if rule.Protocol != "-1" && !strings.EqualFold(rule.Protocol, protocol) && rule.Protocol != protocolNumber(protocol) {
+		return false
+	}
+	if rule.Protocol == "-1" || rule.FromPort == nil || rule.ToPort == nil {
+		return true
+	}
+	return *rule.FromPort <= port && port <= *rule.ToPort
+}
+
+// protocolNumber returns the IANA protocol number that AWS uses in place of the protocol name for some rules.
+func protocolNumber(protocol string) string {
+	switch strings.ToLower(protocol) {
+	case "tcp":
+		return "6"
+	case "udp":
+		return "17"
+	case "icmp":
+		return "1"
+	}
+	return protocol
+}
+
+// describeNetworkInterfaces lists all network interfaces in the region, optionally filtered by VPC.
+func describeNetworkInterfaces(ctx context.Context, svc *ec2.Client, vpcID *string) ([]types.NetworkInterface, error) {
+	var filters []types.Filter
+	if vpcID != nil {
+		filters = append(filters, types.Filter{
+			Name:   aws.String("vpc-id"),
+			Values: []string{*vpcID},
+		})
+	}
+
+	var networkInterfaces []types.NetworkInterface
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(svc, &ec2.DescribeNetworkInterfacesInput{Filters: filters})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return networkInterfaces, err
+		}
+		networkInterfaces = append(networkInterfaces, output.NetworkInterfaces...)
+	}
+	return networkInterfaces, nil
+}
+
+// loadBalancerFromDescription extracts the load balancer identifier from the description that AWS assigns to load
+// balancer network interfaces, e.g. "ELB app/my-alb/50dc6c495c0c9188" or "ELB my-classic-lb".
+func loadBalancerFromDescription(description string) (string, bool) {
+	if !strings.HasPrefix(description, "ELB ") {
+		return "", false
+	}
+	return strings.TrimPrefix(description, "ELB "), true
+}
+
+// appendUnique appends value to values if it is not already present.
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
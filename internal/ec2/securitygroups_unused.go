@@ -0,0 +1,130 @@
+package ec2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Method-Security/methodaws/internal/sts"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// defaultGroupName is the name AWS gives to the security group that is created alongside every VPC.
+const defaultGroupName = "default"
+
+// FindUnusedSecurityGroups reports the security groups across multiple regions that are not attached to any network
+// interface, alongside the default VPC security groups that still have rules. If vpcID is not nil, only security
+// groups associated with that VPC are considered.
+func FindUnusedSecurityGroups(ctx context.Context, cfg aws.Config, vpcID *string, regions []string) (UnusedSecurityGroupReport, error) {
+	report := UnusedSecurityGroupReport{
+		UnusedSecurityGroups:    []UnusedSecurityGroup{},
+		PermissiveDefaultGroups: []PermissiveDefaultGroup{},
+		Errors:                  []string{},
+	}
+
+	id, err := sts.GetAccountID(ctx, cfg)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("Error getting account ID: %v", err))
+		return report, nil
+	}
+	report.AccountID = *id
+
+	if len(regions) == 0 {
+		report.Errors = append(report.Errors, "No regions specified for security group enumeration")
+		return report, nil
+	}
+
+	for _, region := range regions {
+		unused, defaults, errors := FindUnusedSecurityGroupsForRegion(ctx, cfg, vpcID, region)
+		report.UnusedSecurityGroups = append(report.UnusedSecurityGroups, unused...)
+		report.PermissiveDefaultGroups = append(report.PermissiveDefaultGroups, defaults...)
+		report.Errors = append(report.Errors, errors...)
+	}
+
+	return report, nil
+}
+
+// FindUnusedSecurityGroupsForRegion correlates the security groups in a region with the region's network interfaces.
+// Groups that no network interface uses are reported as unused, and default groups that still have rules are
+// reported as permissive. Default groups are never reported as unused because they cannot be deleted.
+func FindUnusedSecurityGroupsForRegion(ctx context.Context, cfg aws.Config, vpcID *string, region string) ([]UnusedSecurityGroup, []PermissiveDefaultGroup, []string) {
+	cfg.Region = region
+	svc := ec2.NewFromConfig(cfg)
+	unused := []UnusedSecurityGroup{}
+	defaults := []PermissiveDefaultGroup{}
+
+	securityGroups, errors := EnumerateSecurityGroupForRegion(ctx, cfg, vpcID, region)
+	if len(errors) > 0 {
+		return unused, defaults, errors
+	}
+
+	networkInterfaces, err := describeNetworkInterfaces(ctx, svc, vpcID)
+	if err != nil {
+		// Without the network interfaces every group would appear unused, so don't report anything
+		errors = append(errors, fmt.Sprintf("Error in region %s: %v", region, err))
+		return unused, defaults, errors
+	}
+
+	attached := map[string]bool{}
+	for _, eni := range networkInterfaces {
+		for _, group := range eni.Groups {
+			attached[aws.ToString(group.GroupId)] = true
+		}
+	}
+
+	// Map every group to the groups whose rules reference it
+	referencedBy := map[string][]string{}
+	for _, sg := range securityGroups {
+		groupID := aws.ToString(sg.GroupId)
+		for _, rule := range NormalizeSecurityGroupRules(sg) {
+			if rule.ReferencedGroupID == nil || *rule.ReferencedGroupID == groupID {
+				continue
+			}
+			referencedBy[*rule.ReferencedGroupID] = appendUnique(referencedBy[*rule.ReferencedGroupID], groupID)
+		}
+	}
+
+	for _, sg := range securityGroups {
+		groupID := aws.ToString(sg.GroupId)
+
+		if aws.ToString(sg.GroupName) == defaultGroupName {
+			rules := NormalizeSecurityGroupRules(sg)
+			if len(rules) > 0 {
+				defaults = append(defaults, PermissiveDefaultGroup{
+					GroupID: groupID,
+					VPCID:   aws.ToString(sg.VpcId),
+					Region:  region,
+					Rules:   rules,
+				})
+			}
+			continue
+		}
+
+		if attached[groupID] {
+			continue
+		}
+
+		referencers := referencedBy[groupID]
+		onlyByUnused := len(referencers) > 0
+		for _, referencer := range referencers {
+			if attached[referencer] {
+				onlyByUnused = false
+				break
+			}
+		}
+
+		if referencers == nil {
+			referencers = []string{}
+		}
+		unused = append(unused, UnusedSecurityGroup{
+			GroupID:                groupID,
+			GroupName:              aws.ToString(sg.GroupName),
+			VPCID:                  aws.ToString(sg.VpcId),
+			Region:                 region,
+			ReferencedBy:           referencers,
+			ReferencedOnlyByUnused: onlyByUnused,
+		})
+	}
+
+	return unused, defaults, errors
+}
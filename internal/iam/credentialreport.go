@@ -0,0 +1,228 @@
+package iam
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	methodaws "github.com/Method-Security/methodaws/generated/go"
+	"github.com/Method-Security/methodaws/internal/sts"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+const (
+	// credentialReportPollInterval and credentialReportMaxPolls bound how long to wait for AWS to generate the report
+	credentialReportPollInterval = 2 * time.Second
+	credentialReportMaxPolls     = 30
+
+	// rootUser is the name the credential report uses for the account's root user
+	rootUser = "<root_account>"
+)
+
+// GetCredentialReport generates the IAM credential report, waiting for AWS to finish generating it if needed, and
+// parses it into an IamCredentialReport. Every user is flagged with the findings that apply to it: root user usage,
+// root access keys or missing root MFA, access keys that have not been rotated or used in staleDays days, and console
+// users without MFA.
+func GetCredentialReport(ctx context.Context, cfg aws.Config, staleDays int) (*methodaws.IamCredentialReport, error) {
+	client := iam.NewFromConfig(cfg)
+	report := methodaws.IamCredentialReport{
+		Users:  []*methodaws.IamCredentialReportUser{},
+		Errors: []string{},
+	}
+
+	accountID, err := sts.GetAccountID(ctx, cfg)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return &report, nil
+	}
+	report.AccountId = aws.ToString(accountID)
+
+	if err := generateCredentialReport(ctx, client); err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return &report, nil
+	}
+
+	output, err := client.GetCredentialReport(ctx, &iam.GetCredentialReportInput{})
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return &report, nil
+	}
+	report.GeneratedTime = output.GeneratedTime
+
+	users, err := parseCredentialReport(output.Content)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return &report, nil
+	}
+
+	now := time.Now()
+	for _, user := range users {
+		user.Findings = credentialReportFindings(user, now, staleDays)
+	}
+	report.Users = users
+
+	return &report, nil
+}
+
+// A utility function that requests a new credential report and polls until AWS reports it as complete. AWS reuses a
+// report generated in the last four hours, in which case it is complete immediately.
+func generateCredentialReport(ctx context.Context, client *iam.Client) error {
+	for poll := 0; poll < credentialReportMaxPolls; poll++ {
+		output, err := client.GenerateCredentialReport(ctx, &iam.GenerateCredentialReportInput{})
+		if err != nil {
+			return fmt.Errorf("error generating credential report: %v", err)
+		}
+		if output.State == types.ReportStateTypeComplete {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(credentialReportPollInterval):
+		}
+	}
+	return errors.New("timed out waiting for the credential report to be generated")
+}
+
+// A utility function that parses the CSV content of a credential report into one IamCredentialReportUser per row. The
+// report marks missing values as N/A, no_information or not_supported, which are parsed as unset.
+func parseCredentialReport(content []byte) ([]*methodaws.IamCredentialReportUser, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing credential report: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("error parsing credential report: the report is empty")
+	}
+
+	columns := map[string]int{}
+	for i, column := range records[0] {
+		columns[column] = i
+	}
+
+	users := []*methodaws.IamCredentialReportUser{}
+	for _, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+
+		user := &methodaws.IamCredentialReportUser{
+			User:                 field("user"),
+			Arn:                  field("arn"),
+			IsRoot:               field("user") == rootUser,
+			UserCreationTime:     credentialReportTime(field("user_creation_time")),
+			PasswordEnabled:      credentialReportBool(field("password_enabled")),
+			PasswordLastUsed:     credentialReportTime(field("password_last_used")),
+			PasswordLastChanged:  credentialReportTime(field("password_last_changed")),
+			PasswordNextRotation: credentialReportTime(field("password_next_rotation")),
+			MfaActive:            credentialReportBool(field("mfa_active")),
+			AccessKeys:           []*methodaws.IamCredentialReportAccessKey{},
+			Certificates:         []*methodaws.IamCredentialReportCertificate{},
+			Findings:             []methodaws.IamCredentialReportFinding{},
+		}
+		for _, key := range []string{"access_key_1", "access_key_2"} {
+			user.AccessKeys = append(user.AccessKeys, &methodaws.IamCredentialReportAccessKey{
+				Active:          credentialReportBool(field(key + "_active")),
+				LastRotated:     credentialReportTime(field(key + "_last_rotated")),
+				LastUsedDate:    credentialReportTime(field(key + "_last_used_date")),
+				LastUsedRegion:  credentialReportString(field(key + "_last_used_region")),
+				LastUsedService: credentialReportString(field(key + "_last_used_service")),
+			})
+		}
+		for _, cert := range []string{"cert_1", "cert_2"} {
+			user.Certificates = append(user.Certificates, &methodaws.IamCredentialReportCertificate{
+				Active:      credentialReportBool(field(cert + "_active")),
+				LastRotated: credentialReportTime(field(cert + "_last_rotated")),
+			})
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// A utility function that flags the hygiene issues of a single user in the credential report. Access key ages are
+// recorded on the keys as a side effect.
+func credentialReportFindings(user *methodaws.IamCredentialReportUser, now time.Time, staleDays int) []methodaws.IamCredentialReportFinding {
+	findings := []methodaws.IamCredentialReportFinding{}
+	stale := now.AddDate(0, 0, -staleDays)
+
+	activeKey, staleKey, unusedKey := false, false, false
+	recentlyUsed := user.PasswordLastUsed != nil && user.PasswordLastUsed.After(stale)
+	for _, key := range user.AccessKeys {
+		if key.LastRotated != nil {
+			ageDays := int(now.Sub(*key.LastRotated).Hours() / 24)
+			key.AgeDays = &ageDays
+		}
+		if !key.Active {
+			continue
+		}
+		activeKey = true
+		if key.LastRotated != nil && key.LastRotated.Before(stale) {
+			staleKey = true
+			if key.LastUsedDate == nil || key.LastUsedDate.Before(stale) {
+				unusedKey = true
+			}
+		}
+		if key.LastUsedDate != nil && key.LastUsedDate.After(stale) {
+			recentlyUsed = true
+		}
+	}
+
+	if user.IsRoot {
+		if activeKey {
+			findings = append(findings, methodaws.IamCredentialReportFindingRootAccessKeyActive)
+		}
+		if !user.MfaActive {
+			findings = append(findings, methodaws.IamCredentialReportFindingRootMfaDisabled)
+		}
+		if recentlyUsed {
+			findings = append(findings, methodaws.IamCredentialReportFindingRootRecentlyUsed)
+		}
+	}
+	if staleKey {
+		findings = append(findings, methodaws.IamCredentialReportFindingStaleAccessKey)
+	}
+	if unusedKey {
+		findings = append(findings, methodaws.IamCredentialReportFindingUnusedAccessKey)
+	}
+	if !user.IsRoot && user.PasswordEnabled && !user.MfaActive {
+		findings = append(findings, methodaws.IamCredentialReportFindingConsoleUserWithoutMfa)
+	}
+
+	return findings
+}
+
+// A utility function that parses a credential report timestamp, returning nil for missing values.
+func credentialReportTime(value string) *time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+// A utility function that parses a credential report boolean. Missing values, such as the root user's
+// password_enabled, are false.
+func credentialReportBool(value string) bool {
+	return strings.EqualFold(value, "true")
+}
+
+// A utility function that returns a credential report string, or nil for missing values.
+func credentialReportString(value string) *string {
+	switch value {
+	case "", "N/A", "no_information", "not_supported":
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,257 @@
+package iam
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	methodaws "github.com/Method-Security/methodaws/generated/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const credentialReportHeader = "user,arn,user_creation_time,password_enabled,password_last_used,password_last_changed," +
+	"password_next_rotation,mfa_active,access_key_1_active,access_key_1_last_rotated,access_key_1_last_used_date," +
+	"access_key_1_last_used_region,access_key_1_last_used_service,access_key_2_active,access_key_2_last_rotated," +
+	"access_key_2_last_used_date,access_key_2_last_used_region,access_key_2_last_used_service,cert_1_active," +
+	"cert_1_last_rotated,cert_2_active,cert_2_last_rotated"
+
+func mustTime(t *testing.T, value string) *time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return &parsed
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func TestParseCredentialReport(t *testing.T) {
+	unusedCertificates := []*methodaws.IamCredentialReportCertificate{{}, {}}
+
+	tests := []struct {
+		desc        string
+		giveContent []string
+		want        []*methodaws.IamCredentialReportUser
+		wantError   string
+	}{
+		{
+			desc: "root row with values that are not supported",
+			giveContent: []string{
+				credentialReportHeader,
+				"<root_account>,arn:aws:iam::111111111111:root,2020-01-01T00:00:00+00:00,not_supported,2024-05-01T10:00:00+00:00,not_supported,not_supported,true,false,N/A,N/A,N/A,N/A,false,N/A,N/A,N/A,N/A,false,N/A,false,N/A",
+			},
+			want: []*methodaws.IamCredentialReportUser{{
+				User:             "<root_account>",
+				Arn:              "arn:aws:iam::111111111111:root",
+				IsRoot:           true,
+				UserCreationTime: mustTime(t, "2020-01-01T00:00:00+00:00"),
+				PasswordLastUsed: mustTime(t, "2024-05-01T10:00:00+00:00"),
+				MfaActive:        true,
+				AccessKeys:       []*methodaws.IamCredentialReportAccessKey{{}, {}},
+				Certificates:     unusedCertificates,
+				Findings:         []methodaws.IamCredentialReportFinding{},
+			}},
+		},
+		{
+			desc: "user with an access key and no password use information",
+			giveContent: []string{
+				credentialReportHeader,
+				"alice,arn:aws:iam::111111111111:user/alice,2021-01-01T00:00:00+00:00,true,no_information,2021-01-02T00:00:00+00:00,N/A,false,true,2021-01-03T00:00:00+00:00,2024-04-01T00:00:00+00:00,us-east-1,s3,false,N/A,N/A,N/A,N/A,true,2021-01-04T00:00:00+00:00,false,N/A",
+			},
+			want: []*methodaws.IamCredentialReportUser{{
+				User:                "alice",
+				Arn:                 "arn:aws:iam::111111111111:user/alice",
+				UserCreationTime:    mustTime(t, "2021-01-01T00:00:00+00:00"),
+				PasswordEnabled:     true,
+				PasswordLastChanged: mustTime(t, "2021-01-02T00:00:00+00:00"),
+				AccessKeys: []*methodaws.IamCredentialReportAccessKey{
+					{
+						Active:          true,
+						LastRotated:     mustTime(t, "2021-01-03T00:00:00+00:00"),
+						LastUsedDate:    mustTime(t, "2024-04-01T00:00:00+00:00"),
+						LastUsedRegion:  stringPointer("us-east-1"),
+						LastUsedService: stringPointer("s3"),
+					},
+					{},
+				},
+				Certificates: []*methodaws.IamCredentialReportCertificate{
+					{Active: true, LastRotated: mustTime(t, "2021-01-04T00:00:00+00:00")},
+					{},
+				},
+				Findings: []methodaws.IamCredentialReportFinding{},
+			}},
+		},
+		{
+			desc: "access key used without region or service information",
+			giveContent: []string{
+				credentialReportHeader,
+				"bob,arn:aws:iam::111111111111:user/bob,2021-01-01T00:00:00+00:00,false,N/A,N/A,N/A,false,true,2021-01-03T00:00:00+00:00,N/A,N/A,N/A,false,N/A,N/A,not_supported,no_information,false,N/A,false,N/A",
+			},
+			want: []*methodaws.IamCredentialReportUser{{
+				User:             "bob",
+				Arn:              "arn:aws:iam::111111111111:user/bob",
+				UserCreationTime: mustTime(t, "2021-01-01T00:00:00+00:00"),
+				AccessKeys: []*methodaws.IamCredentialReportAccessKey{
+					{Active: true, LastRotated: mustTime(t, "2021-01-03T00:00:00+00:00")},
+					{},
+				},
+				Certificates: unusedCertificates,
+				Findings:     []methodaws.IamCredentialReportFinding{},
+			}},
+		},
+		{
+			desc:        "missing columns",
+			giveContent: []string{"user,arn", "carol,arn:aws:iam::111111111111:user/carol"},
+			want: []*methodaws.IamCredentialReportUser{{
+				User:         "carol",
+				Arn:          "arn:aws:iam::111111111111:user/carol",
+				AccessKeys:   []*methodaws.IamCredentialReportAccessKey{{}, {}},
+				Certificates: unusedCertificates,
+				Findings:     []methodaws.IamCredentialReportFinding{},
+			}},
+		},
+		{
+			desc:        "header only",
+			giveContent: []string{credentialReportHeader},
+			want:        []*methodaws.IamCredentialReportUser{},
+		},
+		{
+			desc:        "empty report",
+			giveContent: []string{},
+			wantError:   "error parsing credential report: the report is empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			users, err := parseCredentialReport([]byte(strings.Join(tt.giveContent, "\n")))
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, users)
+		})
+	}
+}
+
+func TestCredentialReportFindings(t *testing.T) {
+	now := *mustTime(t, "2024-06-01T00:00:00Z")
+	recent := mustTime(t, "2024-05-15T00:00:00Z")
+	old := mustTime(t, "2023-01-01T00:00:00Z")
+
+	tests := []struct {
+		desc        string
+		giveUser    *methodaws.IamCredentialReportUser
+		want        []methodaws.IamCredentialReportFinding
+		wantAgeDays []*int
+	}{
+		{
+			desc: "root with an active key, no MFA and recent use",
+			giveUser: &methodaws.IamCredentialReportUser{
+				IsRoot:           true,
+				PasswordLastUsed: recent,
+				AccessKeys:       []*methodaws.IamCredentialReportAccessKey{{Active: true, LastRotated: recent}},
+			},
+			want: []methodaws.IamCredentialReportFinding{
+				methodaws.IamCredentialReportFindingRootAccessKeyActive,
+				methodaws.IamCredentialReportFindingRootMfaDisabled,
+				methodaws.IamCredentialReportFindingRootRecentlyUsed,
+			},
+			wantAgeDays: []*int{intPointer(17)},
+		},
+		{
+			desc: "root with MFA and no recent use",
+			giveUser: &methodaws.IamCredentialReportUser{
+				IsRoot:           true,
+				MfaActive:        true,
+				PasswordLastUsed: old,
+				AccessKeys:       []*methodaws.IamCredentialReportAccessKey{{}},
+			},
+			want:        []methodaws.IamCredentialReportFinding{},
+			wantAgeDays: []*int{nil},
+		},
+		{
+			desc: "root used recently with an inactive key",
+			giveUser: &methodaws.IamCredentialReportUser{
+				IsRoot:     true,
+				MfaActive:  true,
+				AccessKeys: []*methodaws.IamCredentialReportAccessKey{{LastRotated: old, LastUsedDate: recent}},
+			},
+			want:        []methodaws.IamCredentialReportFinding{},
+			wantAgeDays: []*int{intPointer(517)},
+		},
+		{
+			desc: "stale key used recently",
+			giveUser: &methodaws.IamCredentialReportUser{
+				AccessKeys: []*methodaws.IamCredentialReportAccessKey{{Active: true, LastRotated: old, LastUsedDate: recent}},
+			},
+			want:        []methodaws.IamCredentialReportFinding{methodaws.IamCredentialReportFindingStaleAccessKey},
+			wantAgeDays: []*int{intPointer(517)},
+		},
+		{
+			desc: "stale key not used recently",
+			giveUser: &methodaws.IamCredentialReportUser{
+				AccessKeys: []*methodaws.IamCredentialReportAccessKey{{Active: true, LastRotated: old, LastUsedDate: old}},
+			},
+			want: []methodaws.IamCredentialReportFinding{
+				methodaws.IamCredentialReportFindingStaleAccessKey,
+				methodaws.IamCredentialReportFindingUnusedAccessKey,
+			},
+			wantAgeDays: []*int{intPointer(517)},
+		},
+		{
+			desc: "stale key never used",
+			giveUser: &methodaws.IamCredentialReportUser{
+				AccessKeys: []*methodaws.IamCredentialReportAccessKey{{}, {Active: true, LastRotated: old}},
+			},
+			want: []methodaws.IamCredentialReportFinding{
+				methodaws.IamCredentialReportFindingStaleAccessKey,
+				methodaws.IamCredentialReportFindingUnusedAccessKey,
+			},
+			wantAgeDays: []*int{nil, intPointer(517)},
+		},
+		{
+			desc: "inactive stale key",
+			giveUser: &methodaws.IamCredentialReportUser{
+				AccessKeys: []*methodaws.IamCredentialReportAccessKey{{LastRotated: old}},
+			},
+			want:        []methodaws.IamCredentialReportFinding{},
+			wantAgeDays: []*int{intPointer(517)},
+		},
+		{
+			desc: "console user without MFA",
+			giveUser: &methodaws.IamCredentialReportUser{
+				PasswordEnabled: true,
+				AccessKeys:      []*methodaws.IamCredentialReportAccessKey{},
+			},
+			want:        []methodaws.IamCredentialReportFinding{methodaws.IamCredentialReportFindingConsoleUserWithoutMfa},
+			wantAgeDays: []*int{},
+		},
+		{
+			desc: "console user with MFA",
+			giveUser: &methodaws.IamCredentialReportUser{
+				PasswordEnabled: true,
+				MfaActive:       true,
+				AccessKeys:      []*methodaws.IamCredentialReportAccessKey{},
+			},
+			want:        []methodaws.IamCredentialReportFinding{},
+			wantAgeDays: []*int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.want, credentialReportFindings(tt.giveUser, now, 90))
+			ageDays := []*int{}
+			for _, key := range tt.giveUser.AccessKeys {
+				ageDays = append(ageDays, key.AgeDays)
+			}
+			assert.Equal(t, tt.wantAgeDays, ageDays)
+		})
+	}
+}
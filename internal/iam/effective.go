@@ -0,0 +1,70 @@
+package iam
+
+import (
+	"fmt"
+
+	"github.com/Method-Security/methodaws/internal/iam/policy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// EffectivePermissions computes the effective permissions of an IAM principal, identified by its ARN, from its attached
+// and inline policies, after applying its permission boundary, if any. Policies whose documents cannot be parsed are
+// skipped and returned as non-fatal errors. boundaryUnknown reports that the principal may have a permission boundary
+// that could not be retrieved; the summary is then marked as such, as it is when the boundary cannot be parsed, and
+// admin access is not reported since the boundary may restrict it.
+func EffectivePermissions(owner string, attached []PolicyResource, inline []*InlinePolicy, boundary *PolicyResource, boundaryUnknown bool) (policy.PermissionSummary, []string) {
+	identity, errs := managedPolicyDocuments(attached)
+	inlineDocuments, inlineErrors := inlinePolicyDocuments(owner, inline)
+	identity = append(identity, inlineDocuments...)
+	errs = append(errs, inlineErrors...)
+
+	var boundaryDocument *policy.NamedDocument
+	if boundary != nil {
+		documents, boundaryErrors := managedPolicyDocuments([]PolicyResource{*boundary})
+		errs = append(errs, boundaryErrors...)
+		if len(documents) == 1 {
+			boundaryDocument = &documents[0]
+		} else {
+			boundaryUnknown = true
+		}
+	}
+
+	summary := policy.Summarize(identity, boundaryDocument)
+	if boundaryUnknown {
+		summary.BoundaryUnknown = true
+		summary.AdminAccess = false
+	}
+	return summary, errs
+}
+
+// A utility function that parses the decoded default version of each managed policy, named by the policy's ARN.
+// Policies whose documents cannot be parsed are skipped and returned as non-fatal errors.
+func managedPolicyDocuments(policies []PolicyResource) ([]policy.NamedDocument, []string) {
+	documents := []policy.NamedDocument{}
+	errs := []string{}
+	for _, policyResource := range policies {
+		document, err := policy.Parse(aws.ToString(policyResource.PolicyVersion.Document))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error parsing policy %s: %v", aws.ToString(policyResource.Policy.Arn), err))
+			continue
+		}
+		documents = append(documents, policy.NamedDocument{Name: aws.ToString(policyResource.Policy.Arn), Document: document})
+	}
+	return documents, errs
+}
+
+// A utility function that parses the inline policies of a principal or group, identified by its ARN, named by their
+// policy name. Policies that cannot be parsed are skipped and returned as non-fatal errors.
+func inlinePolicyDocuments(owner string, inlinePolicies []*InlinePolicy) ([]policy.NamedDocument, []string) {
+	documents := []policy.NamedDocument{}
+	errs := []string{}
+	for _, inlinePolicy := range inlinePolicies {
+		document, err := policy.Parse(inlinePolicy.Policy)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error parsing inline policy %s/%s: %v", owner, inlinePolicy.PolicyName, err))
+			continue
+		}
+		documents = append(documents, policy.NamedDocument{Name: inlinePolicy.PolicyName, Document: document})
+	}
+	return documents, errs
+}
@@ -0,0 +1,48 @@
+package iam
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// EnumerateIam retrieves all IAM roles, users and groups available to the caller. It returns an AWSResourceReport
+// struct that contains the roles from EnumerateIamRoles alongside every user and group, their policies, and any
+// non-fatal errors that occurred during the execution of the function.
+func EnumerateIam(ctx context.Context, cfg aws.Config) (*AWSResourceReport, error) {
+	report, err := EnumerateIamRoles(ctx, cfg)
+	if err != nil {
+		return report, err
+	}
+	client := iam.NewFromConfig(cfg)
+	policies := report.Resources.Policies.Policies
+	report.Resources.Users = []UserResource{}
+	report.Resources.Groups = []GroupResource{}
+
+	users, err := GetAllUsers(ctx, client)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	for _, user := range users {
+		userResource, userPolicies, errs := EnrichUser(ctx, cfg, &user)
+		report.Errors = append(report.Errors, errs...)
+		policies = append(policies, userPolicies...)
+		report.Resources.Users = append(report.Resources.Users, userResource)
+	}
+
+	groups, err := GetAllGroups(ctx, client)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	for _, group := range groups {
+		groupResource, groupPolicies, errs := EnrichGroupWithPolicies(ctx, cfg, &group)
+		report.Errors = append(report.Errors, errs...)
+		policies = append(policies, groupPolicies...)
+		report.Resources.Groups = append(report.Resources.Groups, groupResource)
+	}
+
+	report.Resources.Policies.Policies = distinctPoliciesFromResource(policies)
+
+	return report, nil
+}
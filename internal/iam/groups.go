@@ -0,0 +1,65 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// GetAllGroups retrieves all Groups that are available to the caller.
+func GetAllGroups(ctx context.Context, client *iam.Client) ([]types.Group, error) {
+	groups := []types.Group{}
+
+	output, err := client.ListGroups(ctx, &iam.ListGroupsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	groups = append(groups, output.Groups...)
+
+	for output.IsTruncated {
+		output, err = client.ListGroups(ctx, &iam.ListGroupsInput{Marker: output.Marker})
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, output.Groups...)
+	}
+
+	return groups, nil
+}
+
+// EnrichGroupWithPolicies retrieves the attached and inline policies for a given IAM group. It returns a GroupResource
+// struct, a slice of PolicyResource structs that contain the attached policies for the group, and any non-fatal errors
+// that occurred.
+func EnrichGroupWithPolicies(ctx context.Context, cfg aws.Config, group *types.Group) (GroupResource, []PolicyResource, []string) {
+	groupName := aws.ToString(group.GroupName)
+	groupResource := GroupResource{
+		Group:                *group,
+		AttachedPoliciesArns: []string{},
+		InlinePolicies:       []*InlinePolicy{},
+	}
+
+	policyReport := GetAttachedPoliciesForGroup(ctx, cfg, groupName)
+	errs := policyReport.Errors
+	for _, policy := range policyReport.Policies {
+		groupResource.AttachedPoliciesArns = append(groupResource.AttachedPoliciesArns, *policy.Policy.Arn)
+	}
+
+	inlinePolicies, err := GetInlinePoliciesForGroup(ctx, cfg, groupName)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("Error getting inline policies for group %s: %v", groupName, err))
+	}
+	for _, inlinePolicy := range inlinePolicies {
+		decoded, err := decodeInlinePolicy(inlinePolicy.PolicyName, inlinePolicy.PolicyDocument)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error decoding inline policy %s/%s: %v", aws.ToString(group.Arn), aws.ToString(inlinePolicy.PolicyName), err))
+			continue
+		}
+		groupResource.InlinePolicies = append(groupResource.InlinePolicies, decoded)
+	}
+
+	return groupResource, policyReport.Policies, errs
+}
@@ -55,19 +55,127 @@ func GetAttachedPoliciesForRole(ctx context.Context, cfg aws.Config, roleName st
 			Errors:   errors,
 		}
 	}
-	for _, policy := range attachedPolicyOutput.AttachedPolicies {
-		policyOutput, err := client.GetPolicy(ctx, &iam.GetPolicyInput{PolicyArn: policy.PolicyArn})
+	policies, policyErrors := getPolicyResources(ctx, client, attachedPolicyArns(attachedPolicyOutput.AttachedPolicies))
+	errors = append(errors, policyErrors...)
+
+	return &PolicyReport{
+		Policies: policies,
+		Errors:   errors,
+	}
+}
+
+// GetAttachedPoliciesForUser captures any policies that have been attached to a given IAM user. It returns a
+// PolicyReport struct that contains the attached policies and any non-fatal errors that occurred during the execution
+// of the function.
+func GetAttachedPoliciesForUser(ctx context.Context, cfg aws.Config, userName string) *PolicyReport {
+	client := iam.NewFromConfig(cfg)
+	attachedPolicyOutput, err := client.ListAttachedUserPolicies(ctx, &iam.ListAttachedUserPoliciesInput{UserName: &userName})
+	if err != nil {
+		return &PolicyReport{
+			Policies: []PolicyResource{},
+			Errors:   []string{err.Error()},
+		}
+	}
+
+	policies, errors := getPolicyResources(ctx, client, attachedPolicyArns(attachedPolicyOutput.AttachedPolicies))
+	return &PolicyReport{
+		Policies: policies,
+		Errors:   errors,
+	}
+}
+
+// GetAttachedPoliciesForGroup captures any policies that have been attached to a given IAM group. It returns a
+// PolicyReport struct that contains the attached policies and any non-fatal errors that occurred during the execution
+// of the function.
+func GetAttachedPoliciesForGroup(ctx context.Context, cfg aws.Config, groupName string) *PolicyReport {
+	client := iam.NewFromConfig(cfg)
+	attachedPolicyOutput, err := client.ListAttachedGroupPolicies(ctx, &iam.ListAttachedGroupPoliciesInput{GroupName: &groupName})
+	if err != nil {
+		return &PolicyReport{
+			Policies: []PolicyResource{},
+			Errors:   []string{err.Error()},
+		}
+	}
+
+	policies, errors := getPolicyResources(ctx, client, attachedPolicyArns(attachedPolicyOutput.AttachedPolicies))
+	return &PolicyReport{
+		Policies: policies,
+		Errors:   errors,
+	}
+}
+
+// GetInlinePoliciesForUser captures any policies that have been inlined within a given IAM user. It returns a slice of
+// the AWS GetUserPolicyOutput struct. If the client is unable to list policies for the user, it will return an error.
+func GetInlinePoliciesForUser(ctx context.Context, cfg aws.Config, userName string) ([]*iam.GetUserPolicyOutput, error) {
+	client := iam.NewFromConfig(cfg)
+	userPolicyOutput, err := client.ListUserPolicies(ctx, &iam.ListUserPoliciesInput{UserName: &userName})
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]*iam.GetUserPolicyOutput, 0)
+	for _, policyName := range userPolicyOutput.PolicyNames {
+		policy, err := client.GetUserPolicy(ctx, &iam.GetUserPolicyInput{UserName: &userName, PolicyName: &policyName})
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// GetInlinePoliciesForGroup captures any policies that have been inlined within a given IAM group. It returns a slice
+// of the AWS GetGroupPolicyOutput struct. If the client is unable to list policies for the group, it will return an
+// error.
+func GetInlinePoliciesForGroup(ctx context.Context, cfg aws.Config, groupName string) ([]*iam.GetGroupPolicyOutput, error) {
+	client := iam.NewFromConfig(cfg)
+	groupPolicyOutput, err := client.ListGroupPolicies(ctx, &iam.ListGroupPoliciesInput{GroupName: &groupName})
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]*iam.GetGroupPolicyOutput, 0)
+	for _, policyName := range groupPolicyOutput.PolicyNames {
+		policy, err := client.GetGroupPolicy(ctx, &iam.GetGroupPolicyInput{GroupName: &groupName, PolicyName: &policyName})
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// A utility function that returns the ARNs of a list of attached policies.
+func attachedPolicyArns(attachedPolicies []types.AttachedPolicy) []string {
+	policyArns := []string{}
+	for _, policy := range attachedPolicies {
+		policyArns = append(policyArns, aws.ToString(policy.PolicyArn))
+	}
+	return policyArns
+}
+
+// A utility function that retrieves each managed policy and decodes its default version. It returns the decoded
+// policies and any non-fatal errors that occurred; a policy that cannot be retrieved or decoded is skipped.
+func getPolicyResources(ctx context.Context, client *iam.Client, policyArns []string) ([]PolicyResource, []string) {
+	policies := make([]PolicyResource, 0)
+	errors := make([]string, 0)
+
+	for _, policyArn := range policyArns {
+		policyOutput, err := client.GetPolicy(ctx, &iam.GetPolicyInput{PolicyArn: aws.String(policyArn)})
 		if err != nil {
 			errors = append(errors, err.Error())
 		}
 		if policyOutput == nil || policyOutput.Policy == nil || policyOutput.Policy.Arn == nil {
-			errors = append(errors, fmt.Sprintf("Failed to get policy for attached policy %s", *policy.PolicyArn))
+			errors = append(errors, fmt.Sprintf("Failed to get policy for attached policy %s", policyArn))
 			continue
 		}
 
-		policyVersionOutput, err := client.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{PolicyArn: policy.PolicyArn, VersionId: policyOutput.Policy.DefaultVersionId})
+		policyVersionOutput, err := client.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{PolicyArn: aws.String(policyArn), VersionId: policyOutput.Policy.DefaultVersionId})
 		if err != nil {
 			errors = append(errors, err.Error())
+			continue
 		}
 
 		decodedPolicyVersion, err := decodePolicyVersion(*policyVersionOutput.PolicyVersion)
@@ -81,10 +189,23 @@ func GetAttachedPoliciesForRole(ctx context.Context, cfg aws.Config, roleName st
 		})
 	}
 
-	return &PolicyReport{
-		Policies: policies,
-		Errors:   errors,
+	return policies, errors
+}
+
+// A utility function that decodes and minifies an inline policy document.
+func decodeInlinePolicy(policyName *string, policyDocument *string) (*InlinePolicy, error) {
+	decoded, err := decodeDocument(policyDocument)
+	if err != nil {
+		return nil, err
 	}
+	minified, err := minifyJSON(*decoded)
+	if err != nil {
+		return nil, err
+	}
+	return &InlinePolicy{
+		PolicyName: aws.ToString(policyName),
+		Policy:     *minified,
+	}, nil
 }
 
 // A utility function that decodes a policy version. It returns a DecodedPolicyVersion struct that contains the decoded
@@ -0,0 +1,206 @@
+package policy
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Method-Security/methodaws/internal/common"
+)
+
+// conditionOperator compares a single request value against a single policy value.
+type conditionOperator func(requestValue string, policyValue string) bool
+
+// conditionOperators are the supported condition operators, keyed by their lowercased name. Negated operators are
+// evaluated as the negation of their positive form, see negatedOperators.
+var conditionOperators = map[string]conditionOperator{
+	"stringequals":             func(r, p string) bool { return r == p },
+	"stringequalsignorecase":   strings.EqualFold,
+	"stringlike":               func(r, p string) bool { return common.WildcardMatch(p, r) },
+	"numericequals":            numericOperator(func(r, p float64) bool { return r == p }),
+	"numericlessthan":          numericOperator(func(r, p float64) bool { return r < p }),
+	"numericlessthanequals":    numericOperator(func(r, p float64) bool { return r <= p }),
+	"numericgreaterthan":       numericOperator(func(r, p float64) bool { return r > p }),
+	"numericgreaterthanequals": numericOperator(func(r, p float64) bool { return r >= p }),
+	"dateequals":               dateOperator(func(r, p time.Time) bool { return r.Equal(p) }),
+	"datelessthan":             dateOperator(func(r, p time.Time) bool { return r.Before(p) }),
+	"datelessthanequals":       dateOperator(func(r, p time.Time) bool { return !r.After(p) }),
+	"dategreaterthan":          dateOperator(func(r, p time.Time) bool { return r.After(p) }),
+	"dategreaterthanequals":    dateOperator(func(r, p time.Time) bool { return !r.Before(p) }),
+	"bool":                     strings.EqualFold,
+	"binaryequals":             func(r, p string) bool { return r == p },
+	"ipaddress":                ipAddressMatches,
+	"arnequals":                func(r, p string) bool { return common.WildcardMatch(p, r) },
+	"arnlike":                  func(r, p string) bool { return common.WildcardMatch(p, r) },
+}
+
+// negatedOperators maps each negated operator to the positive operator it negates.
+var negatedOperators = map[string]string{
+	"stringnotequals":           "stringequals",
+	"stringnotequalsignorecase": "stringequalsignorecase",
+	"stringnotlike":             "stringlike",
+	"numericnotequals":          "numericequals",
+	"datenotequals":             "dateequals",
+	"notipaddress":              "ipaddress",
+	"arnnotequals":              "arnequals",
+	"arnnotlike":                "arnlike",
+}
+
+// EvaluateConditions reports whether every condition of the statement is satisfied by the request context. Condition
+// keys are case-insensitive and must be lowercased in the context. It returns an error for an unsupported operator,
+// in which case the conditions are not satisfied.
+func (s Statement) EvaluateConditions(context map[string][]string) (bool, error) {
+	for operator, conditions := range s.Condition {
+		for key, values := range conditions {
+			satisfied, err := evaluateCondition(operator, context[strings.ToLower(key)], values, context)
+			if err != nil {
+				return false, err
+			}
+			if !satisfied {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// A utility function that evaluates a single condition. The operator may carry a ForAllValues: or ForAnyValue: set
+// qualifier and an IfExists suffix. Without a qualifier, the condition is satisfied when any request value matches any
+// policy value, or for a negated operator when none does. ForAnyValue is satisfied when some request value satisfies
+// the operator, and ForAllValues when every request value does.
+func evaluateCondition(operator string, requestValues []string, policyValues []string, context map[string][]string) (bool, error) {
+	name := strings.ToLower(operator)
+
+	qualifier := ""
+	if prefix, rest, found := strings.Cut(name, ":"); found {
+		qualifier, name = prefix, rest
+	}
+	ifExists := strings.HasSuffix(name, "ifexists")
+	name = strings.TrimSuffix(name, "ifexists")
+
+	if name == "null" {
+		for _, policyValue := range policyValues {
+			if strings.EqualFold(policyValue, "true") == (len(requestValues) == 0) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	negated := false
+	if positive, ok := negatedOperators[name]; ok {
+		negated, name = true, positive
+	}
+	compare, ok := conditionOperators[name]
+	if !ok {
+		return false, fmt.Errorf("unsupported condition operator %s", operator)
+	}
+
+	resolved := make([]string, 0, len(policyValues))
+	for _, policyValue := range policyValues {
+		resolved = append(resolved, substituteVariables(policyValue, context))
+	}
+	matchesAny := func(requestValue string) bool {
+		for _, policyValue := range resolved {
+			if compare(requestValue, policyValue) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(requestValues) == 0 {
+		// ForAllValues is satisfied by an empty set of values and ForAnyValue is not; otherwise a missing key only
+		// satisfies IfExists and negated operators
+		switch qualifier {
+		case "forallvalues":
+			return true, nil
+		case "foranyvalue":
+			return ifExists, nil
+		}
+		return ifExists || negated, nil
+	}
+
+	switch qualifier {
+	case "foranyvalue":
+		for _, requestValue := range requestValues {
+			if matchesAny(requestValue) != negated {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "forallvalues":
+		for _, requestValue := range requestValues {
+			if matchesAny(requestValue) == negated {
+				return false, nil
+			}
+		}
+		return true, nil
+	default:
+		for _, requestValue := range requestValues {
+			if matchesAny(requestValue) {
+				return !negated, nil
+			}
+		}
+		return negated, nil
+	}
+}
+
+// A utility function that builds an operator comparing values as numbers. Values that are not numbers never match.
+func numericOperator(compare func(requestValue float64, policyValue float64) bool) conditionOperator {
+	return func(requestValue string, policyValue string) bool {
+		r, err := strconv.ParseFloat(requestValue, 64)
+		if err != nil {
+			return false
+		}
+		p, err := strconv.ParseFloat(policyValue, 64)
+		if err != nil {
+			return false
+		}
+		return compare(r, p)
+	}
+}
+
+// A utility function that builds an operator comparing values as dates, given in RFC 3339 form or as epoch seconds.
+// Values that are not dates never match.
+func dateOperator(compare func(requestValue time.Time, policyValue time.Time) bool) conditionOperator {
+	return func(requestValue string, policyValue string) bool {
+		r, ok := parseDate(requestValue)
+		if !ok {
+			return false
+		}
+		p, ok := parseDate(policyValue)
+		if !ok {
+			return false
+		}
+		return compare(r, p)
+	}
+}
+
+func parseDate(value string) (time.Time, bool) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, true
+	}
+	if parsed, err := time.Parse("2006-01-02", value); err == nil {
+		return parsed, true
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+	return time.Time{}, false
+}
+
+// A utility function that reports whether an IP address falls within a CIDR block or equals a single address.
+func ipAddressMatches(requestValue string, policyValue string) bool {
+	ip := net.ParseIP(requestValue)
+	if ip == nil {
+		return false
+	}
+	if _, network, err := net.ParseCIDR(policyValue); err == nil {
+		return network.Contains(ip)
+	}
+	policyIP := net.ParseIP(policyValue)
+	return policyIP != nil && policyIP.Equal(ip)
+}
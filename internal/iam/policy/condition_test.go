@@ -0,0 +1,71 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	tests := []struct {
+		desc              string
+		giveOperator      string
+		giveRequestValues []string
+		givePolicyValues  []string
+		giveContext       map[string][]string
+		want              bool
+		wantError         string
+	}{
+		{desc: "string equals", giveOperator: "StringEquals", giveRequestValues: []string{"a"}, givePolicyValues: []string{"b", "a"}, want: true},
+		{desc: "string equals mismatch", giveOperator: "StringEquals", giveRequestValues: []string{"a"}, givePolicyValues: []string{"A"}, want: false},
+		{desc: "string equals ignore case", giveOperator: "StringEqualsIgnoreCase", giveRequestValues: []string{"a"}, givePolicyValues: []string{"A"}, want: true},
+		{desc: "string like", giveOperator: "StringLike", giveRequestValues: []string{"home/alice/file"}, givePolicyValues: []string{"home/alice/*"}, want: true},
+		{desc: "string not equals", giveOperator: "StringNotEquals", giveRequestValues: []string{"a"}, givePolicyValues: []string{"b"}, want: true},
+		{desc: "string not equals match", giveOperator: "StringNotEquals", giveRequestValues: []string{"a"}, givePolicyValues: []string{"a"}, want: false},
+		{desc: "missing key", giveOperator: "StringEquals", givePolicyValues: []string{"a"}, want: false},
+		{desc: "missing key with negated operator", giveOperator: "StringNotEquals", givePolicyValues: []string{"a"}, want: true},
+		{desc: "missing key with if exists", giveOperator: "StringEqualsIfExists", givePolicyValues: []string{"a"}, want: true},
+		{desc: "present key with if exists", giveOperator: "StringEqualsIfExists", giveRequestValues: []string{"b"}, givePolicyValues: []string{"a"}, want: false},
+		{desc: "numeric less than", giveOperator: "NumericLessThan", giveRequestValues: []string{"3"}, givePolicyValues: []string{"10"}, want: true},
+		{desc: "numeric not a number", giveOperator: "NumericLessThan", giveRequestValues: []string{"three"}, givePolicyValues: []string{"10"}, want: false},
+		{desc: "date greater than", giveOperator: "DateGreaterThan", giveRequestValues: []string{"2024-06-01T00:00:00Z"}, givePolicyValues: []string{"2024-01-01"}, want: true},
+		{desc: "date epoch seconds", giveOperator: "DateLessThan", giveRequestValues: []string{"1700000000"}, givePolicyValues: []string{"2024-01-01T00:00:00Z"}, want: true},
+		{desc: "bool", giveOperator: "Bool", giveRequestValues: []string{"false"}, givePolicyValues: []string{"FALSE"}, want: true},
+		{desc: "ip address in range", giveOperator: "IpAddress", giveRequestValues: []string{"10.0.1.5"}, givePolicyValues: []string{"10.0.0.0/16"}, want: true},
+		{desc: "ip address single address", giveOperator: "IpAddress", giveRequestValues: []string{"10.0.1.5"}, givePolicyValues: []string{"10.0.1.5"}, want: true},
+		{desc: "not ip address", giveOperator: "NotIpAddress", giveRequestValues: []string{"192.168.0.1"}, givePolicyValues: []string{"10.0.0.0/8"}, want: true},
+		{desc: "arn like", giveOperator: "ArnLike", giveRequestValues: []string{"arn:aws:iam::123456789012:role/admin"}, givePolicyValues: []string{"arn:aws:iam::*:role/*"}, want: true},
+		{desc: "null true on missing key", giveOperator: "Null", givePolicyValues: []string{"true"}, want: true},
+		{desc: "null false on present key", giveOperator: "Null", giveRequestValues: []string{"a"}, givePolicyValues: []string{"false"}, want: true},
+		{desc: "null true on present key", giveOperator: "Null", giveRequestValues: []string{"a"}, givePolicyValues: []string{"true"}, want: false},
+		{desc: "for any value", giveOperator: "ForAnyValue:StringEquals", giveRequestValues: []string{"x", "b"}, givePolicyValues: []string{"a", "b"}, want: true},
+		{desc: "for any value none match", giveOperator: "ForAnyValue:StringEquals", giveRequestValues: []string{"x", "y"}, givePolicyValues: []string{"a", "b"}, want: false},
+		{desc: "for any value empty set", giveOperator: "ForAnyValue:StringEquals", givePolicyValues: []string{"a"}, want: false},
+		{desc: "for any value negated", giveOperator: "ForAnyValue:StringNotEquals", giveRequestValues: []string{"a", "x"}, givePolicyValues: []string{"a"}, want: true},
+		{desc: "for all values", giveOperator: "ForAllValues:StringEquals", giveRequestValues: []string{"a", "b"}, givePolicyValues: []string{"a", "b", "c"}, want: true},
+		{desc: "for all values one missing", giveOperator: "ForAllValues:StringEquals", giveRequestValues: []string{"a", "x"}, givePolicyValues: []string{"a", "b"}, want: false},
+		{desc: "for all values empty set", giveOperator: "ForAllValues:StringEquals", givePolicyValues: []string{"a"}, want: true},
+		{desc: "for all values negated", giveOperator: "ForAllValues:StringNotEquals", giveRequestValues: []string{"x", "y"}, givePolicyValues: []string{"a"}, want: true},
+		{
+			desc:              "policy variable",
+			giveOperator:      "StringEquals",
+			giveRequestValues: []string{"alice"},
+			givePolicyValues:  []string{"${aws:username}"},
+			giveContext:       map[string][]string{"aws:username": {"alice"}},
+			want:              true,
+		},
+		{desc: "unsupported operator", giveOperator: "StringSortOf", giveRequestValues: []string{"a"}, givePolicyValues: []string{"a"}, wantError: "unsupported condition operator StringSortOf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			satisfied, err := evaluateCondition(tt.giveOperator, tt.giveRequestValues, tt.givePolicyValues, tt.giveContext)
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, satisfied)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Package policy provides an offline evaluation engine for AWS IAM policies. It parses identity policies, permission
+// boundaries, service control policies and resource policies, and evaluates whether a request is allowed following
+// the AWS policy evaluation logic.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Value is a policy element that may be written as a single value or a list of values. Condition values may also be
+// booleans or numbers, which are kept in their JSON text form.
+type Value []string
+
+// UnmarshalJSON accepts a string, a number, a boolean or a list of them.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch value := raw.(type) {
+	case nil:
+		*v = nil
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			values = append(values, scalar(item))
+		}
+		*v = values
+	default:
+		*v = []string{scalar(value)}
+	}
+	return nil
+}
+
+func scalar(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// Principal is the Principal or NotPrincipal element of a statement, keyed by principal type (AWS, Service, Federated
+// or CanonicalUser). The wildcard principal "*" is stored under the "*" key.
+type Principal map[string]Value
+
+// UnmarshalJSON accepts the wildcard principal "*" or a map of principal types to values.
+func (p *Principal) UnmarshalJSON(data []byte) error {
+	var wildcard string
+	if err := json.Unmarshal(data, &wildcard); err == nil {
+		*p = Principal{wildcard: {wildcard}}
+		return nil
+	}
+	var principals map[string]Value
+	if err := json.Unmarshal(data, &principals); err != nil {
+		return err
+	}
+	*p = principals
+	return nil
+}
+
+// Principals flattens the principal element into a sorted list, prefixing each principal with its type unless it is
+// the wildcard principal, e.g. "AWS:arn:aws:iam::123456789012:root" or "*".
+func (p Principal) Principals() []string {
+	principals := []string{}
+	for principalType, values := range p {
+		for _, value := range values {
+			if principalType == "*" {
+				principals = append(principals, value)
+			} else {
+				principals = append(principals, principalType+":"+value)
+			}
+		}
+	}
+	sort.Strings(principals)
+	return principals
+}
+
+// Statement is a single statement of a policy document.
+type Statement struct {
+	Sid          string                      `json:"Sid"`
+	Effect       string                      `json:"Effect"`
+	Principal    Principal                   `json:"Principal"`
+	NotPrincipal Principal                   `json:"NotPrincipal"`
+	Action       Value                       `json:"Action"`
+	NotAction    Value                       `json:"NotAction"`
+	Resource     Value                       `json:"Resource"`
+	NotResource  Value                       `json:"NotResource"`
+	Condition    map[string]map[string]Value `json:"Condition"`
+}
+
+// IsAllow reports whether the statement allows access.
+func (s Statement) IsAllow() bool {
+	return strings.EqualFold(s.Effect, "Allow")
+}
+
+// IsDeny reports whether the statement denies access.
+func (s Statement) IsDeny() bool {
+	return strings.EqualFold(s.Effect, "Deny")
+}
+
+// Statements is the Statement element of a policy, which may be a single statement or a list of statements.
+type Statements []Statement
+
+// UnmarshalJSON accepts a single statement or a list of statements.
+func (s *Statements) UnmarshalJSON(data []byte) error {
+	var statements []Statement
+	if err := json.Unmarshal(data, &statements); err == nil {
+		*s = statements
+		return nil
+	}
+	var statement Statement
+	if err := json.Unmarshal(data, &statement); err != nil {
+		return err
+	}
+	*s = Statements{statement}
+	return nil
+}
+
+// Document is a parsed IAM policy document.
+type Document struct {
+	Version   string     `json:"Version"`
+	Statement Statements `json:"Statement"`
+}
+
+// Parse parses a policy document from its JSON form.
+func Parse(document string) (*Document, error) {
+	var parsed Document
+	if err := json.Unmarshal([]byte(document), &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing policy document: %v", err)
+	}
+	return &parsed, nil
+}
+
+// ParseFile parses a policy document from a JSON file.
+func ParseFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading policy document %s: %v", path, err)
+	}
+	return Parse(string(data))
+}
@@ -0,0 +1,223 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Decision is the outcome of evaluating a request.
+type Decision string
+
+const (
+	// DecisionAllow means that the request is allowed.
+	DecisionAllow Decision = "ALLOW"
+	// DecisionExplicitDeny means that a statement explicitly denies the request.
+	DecisionExplicitDeny Decision = "EXPLICIT_DENY"
+	// DecisionImplicitDeny means that no statement allows the request, or that a permission boundary or service control
+	// policy does not.
+	DecisionImplicitDeny Decision = "IMPLICIT_DENY"
+	// DecisionInconclusive means that the request would be allowed, but a Deny statement that covers it has conditions
+	// that could not be evaluated, or some of the policies that apply to it are unknown.
+	DecisionInconclusive Decision = "INCONCLUSIVE"
+)
+
+// Source identifies the kind of policy a statement came from.
+type Source string
+
+const (
+	SourceIdentity            Source = "IDENTITY"
+	SourcePermissionsBoundary Source = "PERMISSIONS_BOUNDARY"
+	SourceServiceControl      Source = "SERVICE_CONTROL_POLICY"
+	SourceResource            Source = "RESOURCE"
+)
+
+// Request is the request being evaluated. Context holds the values of condition keys, keyed by the lowercased key
+// name. The aws:PrincipalArn, aws:PrincipalAccount and aws:username keys are derived from the principal when absent,
+// and the aws:ResourceAccount key from the resource's ARN when it has an account. ARNs of S3 buckets and objects have
+// none, so aws:ResourceAccount must be given for the request to be evaluated as cross-account.
+type Request struct {
+	Principal string
+	Action    string
+	Resource  string
+	Context   map[string][]string
+}
+
+// NamedDocument is a policy document alongside the name used to identify it in results, such as its ARN.
+type NamedDocument struct {
+	Name     string
+	Document *Document
+}
+
+// Policies are the policies that apply to a request. A nil permission boundary or resource policy, or an empty list
+// of service control policies, does not restrict the request. Service control policies are evaluated as a single set,
+// so at least one of them must allow the request.
+type Policies struct {
+	Identity               []NamedDocument
+	PermissionsBoundary    *NamedDocument
+	ServiceControlPolicies []NamedDocument
+	Resource               *NamedDocument
+}
+
+// MatchedStatement is a statement that applies to the request.
+type MatchedStatement struct {
+	Source Source `json:"source" yaml:"source"`
+	Policy string `json:"policy" yaml:"policy"`
+	Sid    string `json:"sid,omitempty" yaml:"sid,omitempty"`
+	Effect string `json:"effect" yaml:"effect"`
+}
+
+// Result is the outcome of evaluating a request alongside the statements that apply to it. CrossAccount is nil when the
+// account of the principal or the resource is unknown, in which case the request is evaluated as a same-account
+// request and Errors says so. Errors also lists statements whose conditions could not be evaluated. An Allow statement
+// whose conditions could not be evaluated is treated as not applying, while a Deny statement makes a request that would
+// otherwise be allowed inconclusive.
+type Result struct {
+	Decision          Decision           `json:"decision" yaml:"decision"`
+	Reason            string             `json:"reason" yaml:"reason"`
+	CrossAccount      *bool              `json:"cross_account" yaml:"cross_account"`
+	MatchedStatements []MatchedStatement `json:"matched_statements" yaml:"matched_statements"`
+	Errors            []string           `json:"errors" yaml:"errors"`
+}
+
+// Evaluate decides whether the request is allowed by the policies, following the AWS policy evaluation logic:
+//
+//  1. An explicit deny in any policy denies the request.
+//  2. When service control policies are present, one of them must allow the request.
+//  3. When the resource belongs to another account than the principal, both an identity policy and the resource
+//     policy must allow the request.
+//  4. When a permission boundary is present, it must allow the request.
+//  5. Otherwise, an identity policy or the resource policy must allow the request. The decision is inconclusive when a
+//     Deny statement whose conditions could not be evaluated covers the request.
+//
+// Permission boundaries are applied to access granted by resource policies as well, which is stricter than AWS for
+// resource policies that name an IAM user or role session directly.
+func Evaluate(request Request, policies Policies) Result {
+	request.Context = requestContext(request)
+	result := Result{
+		MatchedStatements: []MatchedStatement{},
+		Errors:            []string{},
+	}
+
+	identityAllowed, identityDenied, identityMaybeDenied := evaluateDocuments(SourceIdentity, policies.Identity, request, &result)
+	denied, maybeDenied := identityDenied, identityMaybeDenied
+
+	boundaryAllowed := true
+	if policies.PermissionsBoundary != nil {
+		var boundaryDenied, boundaryMaybeDenied bool
+		boundaryAllowed, boundaryDenied, boundaryMaybeDenied = evaluateDocuments(SourcePermissionsBoundary, []NamedDocument{*policies.PermissionsBoundary}, request, &result)
+		denied, maybeDenied = denied || boundaryDenied, maybeDenied || boundaryMaybeDenied
+	}
+
+	scpAllowed := true
+	if len(policies.ServiceControlPolicies) > 0 {
+		var scpDenied, scpMaybeDenied bool
+		scpAllowed, scpDenied, scpMaybeDenied = evaluateDocuments(SourceServiceControl, policies.ServiceControlPolicies, request, &result)
+		denied, maybeDenied = denied || scpDenied, maybeDenied || scpMaybeDenied
+	}
+
+	resourceAllowed := false
+	if policies.Resource != nil {
+		var resourceDenied, resourceMaybeDenied bool
+		resourceAllowed, resourceDenied, resourceMaybeDenied = evaluateDocuments(SourceResource, []NamedDocument{*policies.Resource}, request, &result)
+		denied, maybeDenied = denied || resourceDenied, maybeDenied || resourceMaybeDenied
+	}
+
+	crossAccount := false
+	principalAccount := contextValue(request.Context, "aws:principalaccount")
+	resourceAccount := contextValue(request.Context, "aws:resourceaccount")
+	if principalAccount != "" && resourceAccount != "" {
+		crossAccount = principalAccount != resourceAccount
+		result.CrossAccount = &crossAccount
+	} else {
+		result.Errors = append(result.Errors, fmt.Sprintf("The account of the principal or of resource %s is unknown, so the request was evaluated as a same-account request; set aws:ResourceAccount to evaluate it as cross-account", request.Resource))
+	}
+
+	switch {
+	case denied:
+		result.Decision, result.Reason = DecisionExplicitDeny, "A statement explicitly denies the request"
+	case !scpAllowed:
+		result.Decision, result.Reason = DecisionImplicitDeny, "No service control policy allows the request"
+	case crossAccount && !(identityAllowed && resourceAllowed):
+		result.Decision, result.Reason = DecisionImplicitDeny, "Cross-account requests must be allowed by both an identity policy and the resource policy"
+	case !boundaryAllowed:
+		result.Decision, result.Reason = DecisionImplicitDeny, "The permission boundary does not allow the request"
+	case (identityAllowed || resourceAllowed) && maybeDenied:
+		result.Decision, result.Reason = DecisionInconclusive, "A Deny statement whose conditions could not be evaluated may deny the request"
+	case identityAllowed || resourceAllowed:
+		result.Decision, result.Reason = DecisionAllow, "The request is allowed"
+	default:
+		result.Decision, result.Reason = DecisionImplicitDeny, "No policy allows the request"
+	}
+	return result
+}
+
+// A utility function that evaluates a set of documents of a single kind, recording the statements that apply to the
+// request. It reports whether any statement allows the request, whether any statement denies it and whether a Deny
+// statement whose conditions could not be evaluated covers it.
+func evaluateDocuments(source Source, documents []NamedDocument, request Request, result *Result) (bool, bool, bool) {
+	allowed, denied, maybeDenied := false, false, false
+	for _, document := range documents {
+		if document.Document == nil {
+			continue
+		}
+		for _, statement := range document.Document.Statement {
+			if source == SourceResource && !statement.MatchesPrincipal(request.Principal) {
+				continue
+			}
+			if !statement.MatchesAction(request.Action) || !statement.MatchesResource(request.Resource, request.Context) {
+				continue
+			}
+			satisfied, err := statement.EvaluateConditions(request.Context)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("Error evaluating statement %s of %s: %v", statement.Sid, document.Name, err))
+				maybeDenied = maybeDenied || statement.IsDeny()
+				continue
+			}
+			if !satisfied {
+				continue
+			}
+
+			allowed = allowed || statement.IsAllow()
+			denied = denied || statement.IsDeny()
+			result.MatchedStatements = append(result.MatchedStatements, MatchedStatement{
+				Source: source,
+				Policy: document.Name,
+				Sid:    statement.Sid,
+				Effect: statement.Effect,
+			})
+		}
+	}
+	return allowed, denied, maybeDenied
+}
+
+// A utility function that returns a copy of the request context with lowercased keys, adding the keys that can be
+// derived from the principal's ARN.
+func requestContext(request Request) map[string][]string {
+	context := map[string][]string{}
+	for key, values := range request.Context {
+		context[strings.ToLower(key)] = values
+	}
+	defaults := map[string]string{
+		"aws:principalarn":     request.Principal,
+		"aws:principalaccount": ARNAccount(request.Principal),
+		"aws:resourceaccount":  ARNAccount(request.Resource),
+	}
+	if _, name, found := strings.Cut(request.Principal, ":user/"); found {
+		defaults["aws:username"] = name[strings.LastIndex(name, "/")+1:]
+	}
+	for key, value := range defaults {
+		if _, ok := context[key]; !ok && value != "" {
+			context[key] = []string{value}
+		}
+	}
+	return context
+}
+
+// A utility function that returns the single value of a condition key in the request context, or an empty string if
+// the key has no value or several values.
+func contextValue(context map[string][]string, key string) string {
+	if values := context[key]; len(values) == 1 {
+		return values[0]
+	}
+	return ""
+}
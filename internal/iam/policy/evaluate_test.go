@@ -0,0 +1,206 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRole   = "arn:aws:iam::111111111111:role/app"
+	testUser   = "arn:aws:iam::111111111111:user/alice"
+	testBucket = "arn:aws:s3:::example-bucket"
+	testObject = "arn:aws:s3:::example-bucket/key"
+)
+
+func mustParse(t *testing.T, document string) *Document {
+	t.Helper()
+	parsed, err := Parse(document)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestEvaluate(t *testing.T) {
+	allowGetObject := `{"Statement":{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::example-bucket/*"}}`
+	allowAll := `{"Statement":{"Effect":"Allow","Action":"*","Resource":"*"}}`
+
+	tests := []struct {
+		desc             string
+		giveRequest      Request
+		giveIdentity     []string
+		giveBoundary     string
+		giveSCPs         []string
+		giveResource     string
+		wantDecision     Decision
+		wantCrossAccount *bool
+		wantErrors       int
+	}{
+		{
+			desc:         "identity allow",
+			giveRequest:  Request{Principal: testRole, Action: "s3:GetObject", Resource: testObject},
+			giveIdentity: []string{allowGetObject},
+			wantDecision: DecisionAllow,
+			wantErrors:   1,
+		},
+		{
+			desc:         "no matching statement",
+			giveRequest:  Request{Principal: testRole, Action: "s3:PutObject", Resource: testObject},
+			giveIdentity: []string{allowGetObject},
+			wantDecision: DecisionImplicitDeny,
+			wantErrors:   1,
+		},
+		{
+			desc:        "explicit deny wins",
+			giveRequest: Request{Principal: testRole, Action: "s3:GetObject", Resource: testObject},
+			giveIdentity: []string{
+				allowAll,
+				`{"Statement":{"Effect":"Deny","Action":"s3:*","Resource":"*"}}`,
+			},
+			wantDecision: DecisionExplicitDeny,
+			wantErrors:   1,
+		},
+		{
+			desc:         "deny with not action",
+			giveRequest:  Request{Principal: testRole, Action: "iam:CreateUser", Resource: "*"},
+			giveIdentity: []string{allowAll, `{"Statement":{"Effect":"Deny","NotAction":"s3:*","Resource":"*"}}`},
+			wantDecision: DecisionExplicitDeny,
+			wantErrors:   1,
+		},
+		{
+			desc:         "policy variable in resource",
+			giveRequest:  Request{Principal: testUser, Action: "s3:GetObject", Resource: "arn:aws:s3:::example-bucket/home/alice/notes"},
+			giveIdentity: []string{`{"Statement":{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::example-bucket/home/${aws:username}/*"}}`},
+			wantDecision: DecisionAllow,
+			wantErrors:   1,
+		},
+		{
+			desc: "unsatisfied condition",
+			giveRequest: Request{
+				Principal: testRole,
+				Action:    "s3:GetObject",
+				Resource:  testObject,
+				Context:   map[string][]string{"aws:SourceIp": {"192.168.0.1"}},
+			},
+			giveIdentity: []string{`{"Statement":{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Condition":{"IpAddress":{"aws:SourceIp":"10.0.0.0/8"}}}}`},
+			wantDecision: DecisionImplicitDeny,
+			wantErrors:   1,
+		},
+		{
+			desc:         "permission boundary does not allow",
+			giveRequest:  Request{Principal: testRole, Action: "iam:CreateUser", Resource: "*"},
+			giveIdentity: []string{allowAll},
+			giveBoundary: `{"Statement":{"Effect":"Allow","Action":"s3:*","Resource":"*"}}`,
+			wantDecision: DecisionImplicitDeny,
+			wantErrors:   1,
+		},
+		{
+			desc:         "service control policy does not allow",
+			giveRequest:  Request{Principal: testRole, Action: "ec2:RunInstances", Resource: "*"},
+			giveIdentity: []string{allowAll},
+			giveSCPs:     []string{`{"Statement":{"Effect":"Allow","Action":"s3:*","Resource":"*"}}`},
+			wantDecision: DecisionImplicitDeny,
+			wantErrors:   1,
+		},
+		{
+			desc:         "service control policy denies",
+			giveRequest:  Request{Principal: testRole, Action: "s3:GetObject", Resource: testObject},
+			giveIdentity: []string{allowAll},
+			giveSCPs:     []string{allowAll, `{"Statement":{"Effect":"Deny","Action":"s3:*","Resource":"*"}}`},
+			wantDecision: DecisionExplicitDeny,
+			wantErrors:   1,
+		},
+		{
+			desc:             "same account resource policy allow",
+			giveRequest:      Request{Principal: testRole, Action: "s3:GetObject", Resource: testObject, Context: map[string][]string{"aws:ResourceAccount": {"111111111111"}}},
+			giveResource:     `{"Statement":{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111111111111:role/app"},"Action":"s3:GetObject","Resource":"arn:aws:s3:::example-bucket/*"}}`,
+			wantDecision:     DecisionAllow,
+			wantCrossAccount: boolPointer(false),
+		},
+		{
+			desc:             "cross account needs identity allow",
+			giveRequest:      Request{Principal: testRole, Action: "s3:GetObject", Resource: testObject, Context: map[string][]string{"aws:ResourceAccount": {"222222222222"}}},
+			giveResource:     `{"Statement":{"Effect":"Allow","Principal":{"AWS":"111111111111"},"Action":"s3:GetObject","Resource":"arn:aws:s3:::example-bucket/*"}}`,
+			wantDecision:     DecisionImplicitDeny,
+			wantCrossAccount: boolPointer(true),
+		},
+		{
+			desc:             "cross account allowed by both",
+			giveRequest:      Request{Principal: testRole, Action: "s3:GetObject", Resource: testObject, Context: map[string][]string{"aws:ResourceAccount": {"222222222222"}}},
+			giveIdentity:     []string{allowGetObject},
+			giveResource:     `{"Statement":{"Effect":"Allow","Principal":{"AWS":"111111111111"},"Action":"s3:GetObject","Resource":"arn:aws:s3:::example-bucket/*"}}`,
+			wantDecision:     DecisionAllow,
+			wantCrossAccount: boolPointer(true),
+		},
+		{
+			desc:             "resource account from the resource ARN",
+			giveRequest:      Request{Principal: testRole, Action: "sqs:SendMessage", Resource: "arn:aws:sqs:us-east-1:222222222222:queue"},
+			giveResource:     `{"Statement":{"Effect":"Allow","Principal":"*","Action":"sqs:SendMessage"}}`,
+			wantDecision:     DecisionImplicitDeny,
+			wantCrossAccount: boolPointer(true),
+		},
+		{
+			desc:         "resource policy not naming the principal",
+			giveRequest:  Request{Principal: testRole, Action: "s3:ListBucket", Resource: testBucket},
+			giveResource: `{"Statement":{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111111111111:role/other"},"Action":"s3:ListBucket"}}`,
+			wantDecision: DecisionImplicitDeny,
+			wantErrors:   1,
+		},
+		{
+			desc:             "unsupported condition operator",
+			giveRequest:      Request{Principal: testRole, Action: "s3:GetObject", Resource: testObject, Context: map[string][]string{"aws:ResourceAccount": {"111111111111"}}},
+			giveIdentity:     []string{`{"Statement":{"Sid":"Odd","Effect":"Allow","Action":"*","Resource":"*","Condition":{"StringSortOf":{"aws:username":"alice"}}}}`},
+			wantDecision:     DecisionImplicitDeny,
+			wantCrossAccount: boolPointer(false),
+			wantErrors:       1,
+		},
+		{
+			desc:        "deny with unsupported condition operator",
+			giveRequest: Request{Principal: testRole, Action: "s3:GetObject", Resource: testObject, Context: map[string][]string{"aws:ResourceAccount": {"111111111111"}}},
+			giveIdentity: []string{
+				allowAll,
+				`{"Statement":{"Sid":"Odd","Effect":"Deny","Action":"*","Resource":"*","Condition":{"StringSortOf":{"aws:username":"alice"}}}}`,
+			},
+			wantDecision:     DecisionInconclusive,
+			wantCrossAccount: boolPointer(false),
+			wantErrors:       1,
+		},
+		{
+			desc:        "deny with unsupported condition operator on a request nothing allows",
+			giveRequest: Request{Principal: testRole, Action: "s3:PutObject", Resource: testObject, Context: map[string][]string{"aws:ResourceAccount": {"111111111111"}}},
+			giveIdentity: []string{
+				allowGetObject,
+				`{"Statement":{"Sid":"Odd","Effect":"Deny","Action":"*","Resource":"*","Condition":{"StringSortOf":{"aws:username":"alice"}}}}`,
+			},
+			wantDecision:     DecisionImplicitDeny,
+			wantCrossAccount: boolPointer(false),
+			wantErrors:       1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			policies := Policies{}
+			for _, document := range tt.giveIdentity {
+				policies.Identity = append(policies.Identity, NamedDocument{Name: "identity", Document: mustParse(t, document)})
+			}
+			if tt.giveBoundary != "" {
+				policies.PermissionsBoundary = &NamedDocument{Name: "boundary", Document: mustParse(t, tt.giveBoundary)}
+			}
+			for _, document := range tt.giveSCPs {
+				policies.ServiceControlPolicies = append(policies.ServiceControlPolicies, NamedDocument{Name: "scp", Document: mustParse(t, document)})
+			}
+			if tt.giveResource != "" {
+				policies.Resource = &NamedDocument{Name: "resource", Document: mustParse(t, tt.giveResource)}
+			}
+
+			result := Evaluate(tt.giveRequest, policies)
+			assert.Equal(t, tt.wantDecision, result.Decision)
+			assert.Equal(t, tt.wantCrossAccount, result.CrossAccount)
+			assert.Len(t, result.Errors, tt.wantErrors)
+		})
+	}
+}
+
+func boolPointer(value bool) *bool {
+	return &value
+}
@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Method-Security/methodaws/internal/common"
+)
+
+var (
+	policyVariablePattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+	accountIDPattern      = regexp.MustCompile(`^\d{12}$`)
+)
+
+// ActionMatches reports whether an action pattern matches an action. Action names are case-insensitive.
+func ActionMatches(pattern string, action string) bool {
+	return common.WildcardMatch(strings.ToLower(pattern), strings.ToLower(action))
+}
+
+// MatchesAction reports whether the statement's Action or NotAction element covers the action.
+func (s Statement) MatchesAction(action string) bool {
+	if len(s.NotAction) > 0 {
+		for _, pattern := range s.NotAction {
+			if ActionMatches(pattern, action) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, pattern := range s.Action {
+		if ActionMatches(pattern, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesResource reports whether the statement's Resource or NotResource element covers the resource. Policy
+// variables such as ${aws:username} are replaced with the request's context values first. A statement without either
+// element, such as a statement in a resource policy, covers every resource.
+func (s Statement) MatchesResource(resource string, context map[string][]string) bool {
+	if len(s.NotResource) > 0 {
+		for _, pattern := range s.NotResource {
+			if common.WildcardMatch(substituteVariables(pattern, context), resource) {
+				return false
+			}
+		}
+		return true
+	}
+	if s.Resource == nil {
+		return true
+	}
+	for _, pattern := range s.Resource {
+		if common.WildcardMatch(substituteVariables(pattern, context), resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesPrincipal reports whether the statement's Principal or NotPrincipal element covers the principal. A statement
+// without either element, such as a statement in an identity policy, covers every principal.
+func (s Statement) MatchesPrincipal(principal string) bool {
+	if s.NotPrincipal != nil {
+		return !principalMatches(s.NotPrincipal, principal)
+	}
+	if s.Principal == nil {
+		return true
+	}
+	return principalMatches(s.Principal, principal)
+}
+
+// A utility function that reports whether a principal element names the principal. The wildcard principal names
+// everyone, and an account ID or account root ARN names every principal in that account.
+func principalMatches(element Principal, principal string) bool {
+	for principalType, values := range element {
+		for _, value := range values {
+			switch {
+			case value == "*":
+				return true
+			case value == principal:
+				return true
+			case principalType == "AWS" && PrincipalAccount(value) != "" && isAccountPrincipal(value) && PrincipalAccount(value) == PrincipalAccount(principal):
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// A utility function that reports whether an AWS principal names a whole account, either by its ID or its root ARN.
+func isAccountPrincipal(principal string) bool {
+	return accountIDPattern.MatchString(principal) || strings.HasSuffix(principal, ":root")
+}
+
+// PrincipalAccount returns the account ID of an AWS principal given as an account ID or an ARN, or an empty string if
+// it has none.
+func PrincipalAccount(principal string) string {
+	if accountIDPattern.MatchString(principal) {
+		return principal
+	}
+	return ARNAccount(principal)
+}
+
+// ARNAccount returns the account ID of an ARN, or an empty string if the ARN has none.
+func ARNAccount(arn string) string {
+	if parts := strings.SplitN(arn, ":", 6); len(parts) == 6 && parts[0] == "arn" {
+		return parts[4]
+	}
+	return ""
+}
+
+// A utility function that replaces the policy variables in a pattern with the request's context values. Variables
+// without a value are left in place, so they never match.
+func substituteVariables(pattern string, context map[string][]string) string {
+	return policyVariablePattern.ReplaceAllStringFunc(pattern, func(variable string) string {
+		key := strings.ToLower(variable[2 : len(variable)-1])
+		switch key {
+		case "*", "?", "$":
+			return key
+		}
+		if values := context[key]; len(values) == 1 {
+			return values[0]
+		}
+		return variable
+	})
+}
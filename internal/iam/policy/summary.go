@@ -0,0 +1,273 @@
+package policy
+
+import (
+	"sort"
+	"strings"
+)
+
+// privilegeActions are the IAM and STS actions that grant or take over permissions. An admin that is denied any of
+// them unconditionally is no longer admin-equivalent, while denies of other actions, such as
+// organizations:LeaveOrganization, leave admin access in place.
+var privilegeActions = []string{
+	"iam:AddUserToGroup",
+	"iam:AttachGroupPolicy",
+	"iam:AttachRolePolicy",
+	"iam:AttachUserPolicy",
+	"iam:CreateAccessKey",
+	"iam:CreateLoginProfile",
+	"iam:CreatePolicyVersion",
+	"iam:PassRole",
+	"iam:PutGroupPolicy",
+	"iam:PutRolePolicy",
+	"iam:PutUserPolicy",
+	"iam:UpdateAssumeRolePolicy",
+	"iam:UpdateLoginProfile",
+	"sts:AssumeRole",
+}
+
+// ServicePermissions summarizes what a set of policies allows and denies within a single service. Actions are the
+// allowed action patterns, with patterns covered by a broader allowed pattern removed; ConditionalActions are allowed
+// only when the statement's conditions are met. Service "*" holds patterns that span every service. Resources are not
+// intersected with the permission boundary, so they may include resources that the boundary does not allow.
+type ServicePermissions struct {
+	Service            string   `json:"service" yaml:"service"`
+	Actions            []string `json:"actions" yaml:"actions"`
+	ConditionalActions []string `json:"conditional_actions" yaml:"conditional_actions"`
+	DeniedActions      []string `json:"denied_actions" yaml:"denied_actions"`
+	Resources          []string `json:"resources" yaml:"resources"`
+	WildcardActions    bool     `json:"wildcard_actions" yaml:"wildcard_actions"`
+	WildcardResources  bool     `json:"wildcard_resources" yaml:"wildcard_resources"`
+}
+
+// PermissionSummary is the effective permissions of a set of identity policies after applying a permission boundary.
+// ExcludedActions lists the actions excluded by Allow statements that use NotAction, which are summarized as allowing
+// every action. AdminAccess is set when every action is allowed on every resource without conditions, and no
+// unconditional deny covers one of the privilegeActions. BoundaryUnknown is set by callers when the principal may have a
+// permission boundary that could not be retrieved or parsed, in which case AdminAccess is not set.
+type PermissionSummary struct {
+	Services            []ServicePermissions `json:"services" yaml:"services"`
+	ExcludedActions     []string             `json:"excluded_actions" yaml:"excluded_actions"`
+	WildcardActions     []string             `json:"wildcard_actions" yaml:"wildcard_actions"`
+	WildcardResources   bool                 `json:"wildcard_resources" yaml:"wildcard_resources"`
+	AdminAccess         bool                 `json:"admin_access" yaml:"admin_access"`
+	PermissionsBoundary string               `json:"permissions_boundary,omitempty" yaml:"permissions_boundary,omitempty"`
+	BoundaryUnknown     bool                 `json:"boundary_unknown" yaml:"boundary_unknown"`
+}
+
+// Summarize computes the effective permissions of a set of identity policies statically, without evaluating a
+// request. Each allowed action pattern is intersected with the permission boundary, if any, so that a pattern broader
+// than the boundary is narrowed to the boundary's patterns. Unconditional denies on every resource remove the actions
+// they cover; every deny except those that use NotAction is listed under the service it applies to. Resources are the
+// union of the Resource elements of the statements allowing a service's actions, and statements that use NotResource
+// are summarized as allowing every resource. The summary over-approximates where patterns overlap only partially.
+func Summarize(identity []NamedDocument, boundary *NamedDocument) PermissionSummary {
+	summary := PermissionSummary{
+		Services:        []ServicePermissions{},
+		ExcludedActions: []string{},
+		WildcardActions: []string{},
+	}
+	services := map[string]*ServicePermissions{}
+	service := func(action string) *ServicePermissions {
+		name := "*"
+		if prefix, _, found := strings.Cut(action, ":"); found {
+			name = strings.ToLower(prefix)
+		}
+		if _, ok := services[name]; !ok {
+			services[name] = &ServicePermissions{
+				Service:            name,
+				Actions:            []string{},
+				ConditionalActions: []string{},
+				DeniedActions:      []string{},
+				Resources:          []string{},
+			}
+		}
+		return services[name]
+	}
+
+	documents := append([]NamedDocument{}, identity...)
+	if boundary != nil && boundary.Document != nil {
+		summary.PermissionsBoundary = boundary.Name
+		documents = append(documents, *boundary)
+	} else {
+		boundary = nil
+	}
+
+	// Denies apply from both the identity policies and the boundary
+	unconditionalDenies := []string{}
+	for _, document := range documents {
+		if document.Document == nil {
+			continue
+		}
+		for _, statement := range document.Document.Statement {
+			if !statement.IsDeny() {
+				continue
+			}
+			for _, action := range statement.Action {
+				permissions := service(action)
+				permissions.DeniedActions = append(permissions.DeniedActions, action)
+				if len(statement.Condition) == 0 && len(statement.NotResource) == 0 && containsWildcard(statement.Resource) {
+					unconditionalDenies = append(unconditionalDenies, action)
+				}
+			}
+		}
+	}
+
+	adminAccess := false
+	for _, document := range identity {
+		if document.Document == nil {
+			continue
+		}
+		for _, statement := range document.Document.Statement {
+			if !statement.IsAllow() {
+				continue
+			}
+			actions := []string(statement.Action)
+			if len(statement.NotAction) > 0 {
+				actions = []string{"*"}
+				summary.ExcludedActions = append(summary.ExcludedActions, statement.NotAction...)
+			}
+			resources := []string(statement.Resource)
+			if len(statement.NotResource) > 0 || resources == nil {
+				resources = []string{"*"}
+			}
+
+			for _, action := range actions {
+				for _, effective := range intersectBoundary(action, boundary) {
+					if covered(effective.action, unconditionalDenies) {
+						continue
+					}
+					conditional := len(statement.Condition) > 0 || effective.conditional
+					permissions := service(effective.action)
+					if conditional {
+						permissions.ConditionalActions = append(permissions.ConditionalActions, effective.action)
+					} else {
+						permissions.Actions = append(permissions.Actions, effective.action)
+					}
+					permissions.Resources = append(permissions.Resources, resources...)
+					if strings.ContainsAny(effective.action, "*?") {
+						permissions.WildcardActions = true
+						summary.WildcardActions = append(summary.WildcardActions, effective.action)
+					}
+					if containsWildcard(resources) {
+						permissions.WildcardResources = true
+						summary.WildcardResources = true
+					}
+					if effective.action == "*" && !conditional && len(statement.NotAction) == 0 && containsWildcard(resources) && len(statement.NotResource) == 0 {
+						adminAccess = true
+					}
+				}
+			}
+		}
+	}
+	summary.AdminAccess = adminAccess
+	for _, action := range privilegeActions {
+		summary.AdminAccess = summary.AdminAccess && !covered(action, unconditionalDenies)
+	}
+
+	for _, permissions := range services {
+		permissions.Actions = broadestPatterns(permissions.Actions)
+		permissions.ConditionalActions = broadestPatterns(permissions.ConditionalActions)
+		permissions.DeniedActions = distinct(permissions.DeniedActions)
+		permissions.Resources = distinct(permissions.Resources)
+		summary.Services = append(summary.Services, *permissions)
+	}
+	sort.Slice(summary.Services, func(i, j int) bool {
+		return summary.Services[i].Service < summary.Services[j].Service
+	})
+	summary.ExcludedActions = distinct(summary.ExcludedActions)
+	summary.WildcardActions = distinct(summary.WildcardActions)
+
+	return summary
+}
+
+// boundedAction is an allowed action pattern after intersecting it with a permission boundary.
+type boundedAction struct {
+	action      string
+	conditional bool
+}
+
+// A utility function that intersects an allowed action pattern with the Allow statements of a permission boundary. A
+// boundary pattern that covers the action keeps it, and an action that covers a boundary pattern is narrowed to it.
+// Without a boundary the action is kept as is.
+func intersectBoundary(action string, boundary *NamedDocument) []boundedAction {
+	if boundary == nil {
+		return []boundedAction{{action: action}}
+	}
+
+	intersection := []boundedAction{}
+	for _, statement := range boundary.Document.Statement {
+		if !statement.IsAllow() {
+			continue
+		}
+		conditional := len(statement.Condition) > 0
+		if len(statement.NotAction) > 0 {
+			if !covered(action, statement.NotAction) {
+				intersection = append(intersection, boundedAction{action, conditional})
+			}
+			continue
+		}
+		for _, pattern := range statement.Action {
+			switch {
+			case ActionMatches(pattern, action):
+				intersection = append(intersection, boundedAction{action, conditional})
+			case ActionMatches(action, pattern):
+				intersection = append(intersection, boundedAction{pattern, conditional})
+			}
+		}
+	}
+	return intersection
+}
+
+// A utility function that reports whether any of the patterns covers the whole of an action pattern.
+func covered(action string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ActionMatches(pattern, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// A utility function that reports whether any of the values is the wildcard "*".
+func containsWildcard(values []string) bool {
+	for _, value := range values {
+		if value == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// A utility function that removes the action patterns covered by a broader pattern in the same list, returning the
+// remaining patterns sorted and without duplicates.
+func broadestPatterns(actions []string) []string {
+	actions = distinct(actions)
+	broadest := []string{}
+	for i, action := range actions {
+		subsumed := false
+		for j, other := range actions {
+			if i != j && !strings.EqualFold(action, other) && ActionMatches(other, action) {
+				subsumed = true
+				break
+			}
+		}
+		if !subsumed {
+			broadest = append(broadest, action)
+		}
+	}
+	return broadest
+}
+
+// A utility function that returns the values sorted and without duplicates.
+func distinct(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -0,0 +1,56 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeAdminAccess(t *testing.T) {
+	allowAll := `{"Statement":{"Effect":"Allow","Action":"*","Resource":"*"}}`
+
+	tests := []struct {
+		desc         string
+		giveIdentity []string
+		giveBoundary string
+		want         bool
+	}{
+		{desc: "allow all", giveIdentity: []string{allowAll}, want: true},
+		{desc: "conditional allow all", giveIdentity: []string{`{"Statement":{"Effect":"Allow","Action":"*","Resource":"*","Condition":{"Bool":{"aws:MultiFactorAuthPresent":"true"}}}}`}, want: false},
+		{desc: "allow all of a service", giveIdentity: []string{`{"Statement":{"Effect":"Allow","Action":"iam:*","Resource":"*"}}`}, want: false},
+		{
+			desc:         "deny of an unrelated action",
+			giveIdentity: []string{allowAll, `{"Statement":{"Effect":"Deny","Action":"organizations:LeaveOrganization","Resource":"*"}}`},
+			want:         true,
+		},
+		{
+			desc:         "deny of a privilege action",
+			giveIdentity: []string{allowAll, `{"Statement":{"Effect":"Deny","Action":"iam:PassRole","Resource":"*"}}`},
+			want:         false,
+		},
+		{
+			desc:         "deny of every IAM action",
+			giveIdentity: []string{allowAll, `{"Statement":{"Effect":"Deny","Action":"iam:*","Resource":"*"}}`},
+			want:         false,
+		},
+		{
+			desc:         "conditional deny of a privilege action",
+			giveIdentity: []string{allowAll, `{"Statement":{"Effect":"Deny","Action":"iam:PassRole","Resource":"*","Condition":{"Bool":{"aws:ViaAWSService":"false"}}}}`},
+			want:         true,
+		},
+		{desc: "boundary limited to a service", giveIdentity: []string{allowAll}, giveBoundary: `{"Statement":{"Effect":"Allow","Action":"s3:*","Resource":"*"}}`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			identity := []NamedDocument{}
+			for _, document := range tt.giveIdentity {
+				identity = append(identity, NamedDocument{Name: "identity", Document: mustParse(t, document)})
+			}
+			var boundary *NamedDocument
+			if tt.giveBoundary != "" {
+				boundary = &NamedDocument{Name: "boundary", Document: mustParse(t, tt.giveBoundary)}
+			}
+			assert.Equal(t, tt.want, Summarize(identity, boundary).AdminAccess)
+		})
+	}
+}
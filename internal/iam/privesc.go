@@ -0,0 +1,511 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Method-Security/methodaws/internal/iam/policy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// PrivescTechnique is a privilege escalation primitive that lets one principal act as, or grant permissions to, another.
+type PrivescTechnique string
+
+const (
+	PrivescAssumeRole             PrivescTechnique = "ASSUME_ROLE"
+	PrivescUpdateAssumeRolePolicy PrivescTechnique = "UPDATE_ASSUME_ROLE_POLICY"
+	PrivescPassRoleEC2            PrivescTechnique = "PASS_ROLE_EC2"
+	PrivescPassRoleLambda         PrivescTechnique = "PASS_ROLE_LAMBDA"
+	PrivescPassRoleCloudFormation PrivescTechnique = "PASS_ROLE_CLOUDFORMATION"
+	PrivescPassRoleGlue           PrivescTechnique = "PASS_ROLE_GLUE"
+	PrivescPassRoleSageMaker      PrivescTechnique = "PASS_ROLE_SAGEMAKER"
+	PrivescCreateAccessKey        PrivescTechnique = "CREATE_ACCESS_KEY"
+	PrivescCreateLoginProfile     PrivescTechnique = "CREATE_LOGIN_PROFILE"
+	PrivescUpdateLoginProfile     PrivescTechnique = "UPDATE_LOGIN_PROFILE"
+	PrivescCreatePolicyVersion    PrivescTechnique = "CREATE_POLICY_VERSION"
+	PrivescAttachUserPolicy       PrivescTechnique = "ATTACH_USER_POLICY"
+	PrivescAttachRolePolicy       PrivescTechnique = "ATTACH_ROLE_POLICY"
+	PrivescAttachGroupPolicy      PrivescTechnique = "ATTACH_GROUP_POLICY"
+	PrivescPutUserPolicy          PrivescTechnique = "PUT_USER_POLICY"
+	PrivescPutRolePolicy          PrivescTechnique = "PUT_ROLE_POLICY"
+	PrivescPutGroupPolicy         PrivescTechnique = "PUT_GROUP_POLICY"
+	PrivescAddUserToAdminGroup    PrivescTechnique = "ADD_USER_TO_ADMIN_GROUP"
+)
+
+// passRoleTechniques are the services that run code as a passed role, alongside the actions needed to create and run
+// that code.
+var passRoleTechniques = []struct {
+	technique PrivescTechnique
+	service   string
+	actions   []string
+}{
+	{PrivescPassRoleEC2, "ec2.amazonaws.com", []string{"ec2:RunInstances"}},
+	{PrivescPassRoleLambda, "lambda.amazonaws.com", []string{"lambda:CreateFunction", "lambda:InvokeFunction"}},
+	{PrivescPassRoleCloudFormation, "cloudformation.amazonaws.com", []string{"cloudformation:CreateStack"}},
+	{PrivescPassRoleGlue, "glue.amazonaws.com", []string{"glue:CreateDevEndpoint"}},
+	{PrivescPassRoleSageMaker, "sagemaker.amazonaws.com", []string{"sagemaker:CreateNotebookInstance", "sagemaker:CreatePresignedNotebookInstanceUrl"}},
+}
+
+// PrivescStep is a single step of an escalation path, in which From uses a technique to act as To. A step whose From
+// and To are the same principal grants that principal admin-equivalent permissions.
+type PrivescStep struct {
+	From      string           `json:"from" yaml:"from"`
+	To        string           `json:"to" yaml:"to"`
+	Technique PrivescTechnique `json:"technique" yaml:"technique"`
+	Actions   []string         `json:"actions" yaml:"actions"`
+	Resource  string           `json:"resource" yaml:"resource"`
+}
+
+// PrivescPath is an escalation path from a principal that is not admin-equivalent to one that is.
+type PrivescPath struct {
+	Start  string        `json:"start" yaml:"start"`
+	Target string        `json:"target" yaml:"target"`
+	Steps  []PrivescStep `json:"steps" yaml:"steps"`
+}
+
+// PrivescReport is a struct that contains the admin-equivalent principals of an account, the escalation paths that
+// lead to them, and any non-fatal errors that occurred. IncompletePrincipals lists the principals some of whose
+// policies, permission boundary or trust policy could not be retrieved or parsed; they are left out of the admin
+// principals and paths. This struct is used to represent the output of the `methodaws iam privesc` subcommand.
+type PrivescReport struct {
+	AccountID            string        `json:"account_id" yaml:"account_id"`
+	AdminPrincipals      []string      `json:"admin_principals" yaml:"admin_principals"`
+	IncompletePrincipals []string      `json:"incomplete_principals" yaml:"incomplete_principals"`
+	Paths                []PrivescPath `json:"paths" yaml:"paths"`
+	Errors               []string      `json:"errors" yaml:"errors"`
+}
+
+// privescPrincipal is an IAM role or user alongside the parsed policies needed to detect escalation techniques.
+type privescPrincipal struct {
+	arn      string
+	isRole   bool
+	policies policy.Policies
+	trust    *policy.Document
+	// managedPolicyArns are the managed policies attached to the principal, directly or through its groups
+	managedPolicyArns []string
+	groups            []GroupResource
+	admin             bool
+	// boundaryAllowsAdmin is set when the principal has no permission boundary, or one that allows everything, so that
+	// granting itself new policies makes it admin-equivalent
+	boundaryAllowsAdmin bool
+	// incomplete is set when some of the principal's policies could not be retrieved or parsed
+	incomplete bool
+	// errors are the distinct errors of the statements whose conditions could not be evaluated while detecting
+	// techniques
+	errors         []string
+	reportedErrors map[string]bool
+}
+
+// DetectPrivilegeEscalation enumerates the account's IAM roles, users and groups and detects the escalation paths from
+// each principal to an admin-equivalent one, i.e. one whose effective permissions allow every action on every
+// resource. Paths are built from the techniques in PrivescTechnique, evaluated offline with the principals' identity
+// policies, permission boundaries and role trust policies. Conditions are evaluated without request context, so
+// statements whose conditions need a context key do not apply, and a technique is not reported when a Deny statement
+// whose conditions cannot be evaluated may prevent it. Policies can only be modified on the principal itself, its
+// groups and the customer managed policies attached to either. Principals with incomplete policies are left out, as
+// their permissions are unknown. If startPrincipals is not empty, only paths from those principals are reported.
+func DetectPrivilegeEscalation(ctx context.Context, cfg aws.Config, startPrincipals []string) (*PrivescReport, error) {
+	report := PrivescReport{
+		AdminPrincipals:      []string{},
+		IncompletePrincipals: []string{},
+		Paths:                []PrivescPath{},
+		Errors:               []string{},
+	}
+
+	enumeration, err := EnumerateIam(ctx, cfg)
+	if err != nil {
+		return &report, err
+	}
+	report.AccountID = enumeration.AccountID
+	report.Errors = append(report.Errors, enumeration.Errors...)
+
+	// The enumeration already reports the parse errors of role policies, and a statement may fail to evaluate for many
+	// techniques, so only add the errors that are not reported yet
+	reported := map[string]bool{}
+	for _, reportedError := range report.Errors {
+		reported[reportedError] = true
+	}
+	addErrors := func(errs []string) {
+		for _, err := range errs {
+			if !reported[err] {
+				reported[err] = true
+				report.Errors = append(report.Errors, err)
+			}
+		}
+	}
+
+	allPrincipals, adminGroups, errs := privescPrincipals(enumeration.Resources)
+	addErrors(errs)
+	principals := []*privescPrincipal{}
+	for _, principal := range allPrincipals {
+		if principal.incomplete {
+			report.IncompletePrincipals = append(report.IncompletePrincipals, principal.arn)
+			continue
+		}
+		principals = append(principals, principal)
+		if principal.admin {
+			report.AdminPrincipals = append(report.AdminPrincipals, principal.arn)
+		}
+	}
+
+	edges := map[string][]PrivescStep{}
+	for _, from := range principals {
+		edges[from.arn] = append(edges[from.arn], selfEscalations(from, adminGroups)...)
+		for _, to := range principals {
+			if from.arn != to.arn {
+				edges[from.arn] = append(edges[from.arn], principalEscalations(from, to)...)
+			}
+		}
+	}
+	for _, principal := range principals {
+		addErrors(principal.errors)
+	}
+
+	starts := map[string]bool{}
+	for _, start := range startPrincipals {
+		starts[start] = true
+	}
+	for _, principal := range principals {
+		if principal.admin || (len(starts) > 0 && !starts[principal.arn]) {
+			continue
+		}
+		report.Paths = append(report.Paths, escalationPaths(principal.arn, principals, edges)...)
+	}
+
+	return &report, nil
+}
+
+// A utility function that builds the principals of an IAM enumeration, sorted by ARN, alongside the ARNs of the groups
+// whose policies are admin-equivalent. Policies and trust policies that cannot be parsed are skipped and returned as
+// non-fatal errors, and the principals and groups they belong to are marked as incomplete.
+func privescPrincipals(resources AWSResources) ([]*privescPrincipal, []string, []string) {
+	// Parse every managed policy once, as it may be attached to many principals
+	managedPolicies, errs := managedPolicyDocuments(resources.Policies.Policies)
+	policyDocuments := map[string]policy.NamedDocument{}
+	for _, document := range managedPolicies {
+		policyDocuments[document.Name] = document
+	}
+	// managedDocuments and boundaryDocument report false when a policy is missing from the enumeration
+	managedDocuments := func(owner string, arns []string) ([]policy.NamedDocument, bool) {
+		documents := []policy.NamedDocument{}
+		complete := true
+		for _, arn := range arns {
+			document, ok := policyDocuments[arn]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("Policy %s of %s could not be retrieved or parsed", arn, owner))
+				complete = false
+				continue
+			}
+			documents = append(documents, document)
+		}
+		return documents, complete
+	}
+	boundaryDocument := func(owner string, boundary *string) (*policy.NamedDocument, bool) {
+		if boundary == nil {
+			return nil, true
+		}
+		document, ok := policyDocuments[*boundary]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("Permission boundary %s of %s could not be retrieved or parsed", *boundary, owner))
+			return nil, false
+		}
+		return &document, true
+	}
+	inlineDocuments := func(owner string, inlinePolicies []*InlinePolicy) ([]policy.NamedDocument, bool) {
+		documents, inlineErrors := inlinePolicyDocuments(owner, inlinePolicies)
+		errs = append(errs, inlineErrors...)
+		return documents, len(inlineErrors) == 0
+	}
+
+	groups := map[string]GroupResource{}
+	groupDocuments := map[string][]policy.NamedDocument{}
+	groupIncomplete := map[string]bool{}
+	adminGroups := []string{}
+	for _, group := range resources.Groups {
+		groupName := aws.ToString(group.Group.GroupName)
+		groupArn := aws.ToString(group.Group.Arn)
+		groups[groupName] = group
+		managed, managedComplete := managedDocuments(groupArn, group.AttachedPoliciesArns)
+		inline, inlineComplete := inlineDocuments(groupArn, group.InlinePolicies)
+		groupDocuments[groupName] = append(managed, inline...)
+		groupIncomplete[groupName] = !managedComplete || !inlineComplete
+		if !groupIncomplete[groupName] && policy.Summarize(groupDocuments[groupName], nil).AdminAccess {
+			adminGroups = append(adminGroups, groupArn)
+		}
+	}
+
+	principals := []*privescPrincipal{}
+	for _, role := range resources.Roles {
+		principal := &privescPrincipal{
+			arn:               aws.ToString(role.Role.Role.Arn),
+			isRole:            true,
+			managedPolicyArns: role.AttachedPoliciesArns,
+		}
+		managed, managedComplete := managedDocuments(principal.arn, role.AttachedPoliciesArns)
+		inline, inlineComplete := inlineDocuments(principal.arn, role.InlinePolicies)
+		principal.policies.Identity = append(managed, inline...)
+		boundaryComplete := true
+		if boundary := role.Role.Role.PermissionsBoundary; boundary != nil {
+			principal.policies.PermissionsBoundary, boundaryComplete = boundaryDocument(principal.arn, boundary.PermissionsBoundaryArn)
+		}
+		// The boundary is also unknown when the role's details could not be retrieved
+		boundaryComplete = boundaryComplete && !role.EffectivePermissions.BoundaryUnknown
+		trustComplete := true
+		if role.Role.DecodedAssumeRolePolicyDocument != nil {
+			trust, err := policy.Parse(*role.Role.DecodedAssumeRolePolicyDocument)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("Error parsing trust policy of role %s: %v", principal.arn, err))
+				trustComplete = false
+			}
+			principal.trust = trust
+		}
+		principal.incomplete = !managedComplete || !inlineComplete || !boundaryComplete || !trustComplete
+		principals = append(principals, principal)
+	}
+	for _, user := range resources.Users {
+		principal := &privescPrincipal{
+			arn:               aws.ToString(user.User.Arn),
+			managedPolicyArns: append([]string{}, user.AttachedPoliciesArns...),
+		}
+		managed, managedComplete := managedDocuments(principal.arn, user.AttachedPoliciesArns)
+		inline, inlineComplete := inlineDocuments(principal.arn, user.InlinePolicies)
+		principal.policies.Identity = append(managed, inline...)
+		principal.incomplete = !managedComplete || !inlineComplete
+		for _, groupName := range user.Groups {
+			group, ok := groups[groupName]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("Group %s of %s could not be retrieved", groupName, principal.arn))
+				principal.incomplete = true
+				continue
+			}
+			principal.groups = append(principal.groups, group)
+			principal.managedPolicyArns = append(principal.managedPolicyArns, group.AttachedPoliciesArns...)
+			principal.policies.Identity = append(principal.policies.Identity, groupDocuments[groupName]...)
+			principal.incomplete = principal.incomplete || groupIncomplete[groupName]
+		}
+		if boundary := user.User.PermissionsBoundary; boundary != nil {
+			var boundaryComplete bool
+			principal.policies.PermissionsBoundary, boundaryComplete = boundaryDocument(principal.arn, boundary.PermissionsBoundaryArn)
+			principal.incomplete = principal.incomplete || !boundaryComplete
+		}
+		principals = append(principals, principal)
+	}
+
+	for _, principal := range principals {
+		principal.admin = policy.Summarize(principal.policies.Identity, principal.policies.PermissionsBoundary).AdminAccess
+		principal.boundaryAllowsAdmin = principal.policies.PermissionsBoundary == nil ||
+			policy.Summarize([]policy.NamedDocument{*principal.policies.PermissionsBoundary}, nil).AdminAccess
+	}
+	sort.Slice(principals, func(i, j int) bool {
+		return principals[i].arn < principals[j].arn
+	})
+	return principals, adminGroups, errs
+}
+
+// A utility function that detects the techniques a principal can use to grant itself admin-equivalent permissions.
+// Users can also add themselves to one of the groups whose policies are admin-equivalent.
+func selfEscalations(principal *privescPrincipal, adminGroups []string) []PrivescStep {
+	steps := []PrivescStep{}
+	if principal.admin || !principal.boundaryAllowsAdmin {
+		return steps
+	}
+	step := func(technique PrivescTechnique, action string, resource string) {
+		if principal.allows(action, resource, nil) {
+			steps = append(steps, PrivescStep{From: principal.arn, To: principal.arn, Technique: technique, Actions: []string{action}, Resource: resource})
+		}
+	}
+
+	for _, policyArn := range distinctStrings(principal.managedPolicyArns) {
+		// AWS managed policies cannot be modified
+		if policy.ARNAccount(policyArn) != "aws" {
+			step(PrivescCreatePolicyVersion, "iam:CreatePolicyVersion", policyArn)
+		}
+	}
+	if principal.isRole {
+		step(PrivescAttachRolePolicy, "iam:AttachRolePolicy", principal.arn)
+		step(PrivescPutRolePolicy, "iam:PutRolePolicy", principal.arn)
+		return steps
+	}
+
+	step(PrivescAttachUserPolicy, "iam:AttachUserPolicy", principal.arn)
+	step(PrivescPutUserPolicy, "iam:PutUserPolicy", principal.arn)
+	for _, group := range principal.groups {
+		step(PrivescAttachGroupPolicy, "iam:AttachGroupPolicy", aws.ToString(group.Group.Arn))
+		step(PrivescPutGroupPolicy, "iam:PutGroupPolicy", aws.ToString(group.Group.Arn))
+	}
+	for _, groupArn := range adminGroups {
+		step(PrivescAddUserToAdminGroup, "iam:AddUserToGroup", groupArn)
+	}
+	return steps
+}
+
+// A utility function that detects the techniques a principal can use to act as another principal.
+func principalEscalations(from *privescPrincipal, to *privescPrincipal) []PrivescStep {
+	steps := []PrivescStep{}
+	step := func(technique PrivescTechnique, actions ...string) {
+		steps = append(steps, PrivescStep{From: from.arn, To: to.arn, Technique: technique, Actions: actions, Resource: to.arn})
+	}
+
+	if !to.isRole {
+		if from.allows("iam:CreateAccessKey", to.arn, nil) {
+			step(PrivescCreateAccessKey, "iam:CreateAccessKey")
+		}
+		if from.allows("iam:CreateLoginProfile", to.arn, nil) {
+			step(PrivescCreateLoginProfile, "iam:CreateLoginProfile")
+		}
+		if from.allows("iam:UpdateLoginProfile", to.arn, nil) {
+			step(PrivescUpdateLoginProfile, "iam:UpdateLoginProfile")
+		}
+		return steps
+	}
+
+	canAssume := from.allows("sts:AssumeRole", to.arn, nil)
+	if to.trusts(from.arn) && (canAssume || to.trustNamesPrincipal(from.arn)) {
+		step(PrivescAssumeRole, "sts:AssumeRole")
+	} else if canAssume && from.allows("iam:UpdateAssumeRolePolicy", to.arn, nil) {
+		step(PrivescUpdateAssumeRolePolicy, "iam:UpdateAssumeRolePolicy", "sts:AssumeRole")
+	}
+
+	for _, technique := range passRoleTechniques {
+		if !to.trusts(technique.service) {
+			continue
+		}
+		if !from.allows("iam:PassRole", to.arn, map[string][]string{"iam:passedtoservice": {technique.service}}) {
+			continue
+		}
+		allowed := true
+		for _, action := range technique.actions {
+			allowed = allowed && from.allows(action, "*", nil)
+		}
+		if allowed {
+			step(technique.technique, append([]string{"iam:PassRole"}, technique.actions...)...)
+		}
+	}
+
+	return steps
+}
+
+// A utility function that finds the shortest escalation path from a principal to every admin-equivalent principal it
+// can reach, including itself through a technique that grants it admin-equivalent permissions.
+func escalationPaths(start string, principals []*privescPrincipal, edges map[string][]PrivescStep) []PrivescPath {
+	admins := map[string]bool{}
+	for _, principal := range principals {
+		admins[principal.arn] = principal.admin
+	}
+	escalated := map[string]bool{}
+
+	paths := []PrivescPath{}
+	previous := map[string]PrivescStep{start: {}}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		steps := []PrivescStep{}
+		for node := current; node != start; node = previous[node].From {
+			steps = append([]PrivescStep{previous[node]}, steps...)
+		}
+		if admins[current] {
+			paths = append(paths, PrivescPath{Start: start, Target: current, Steps: steps})
+			continue
+		}
+
+		for _, edge := range edges[current] {
+			if edge.To == current {
+				// Only report the first technique that grants a principal admin-equivalent permissions
+				if !escalated[current] {
+					escalated[current] = true
+					paths = append(paths, PrivescPath{Start: start, Target: current, Steps: append(append([]PrivescStep{}, steps...), edge)})
+				}
+				continue
+			}
+			if _, visited := previous[edge.To]; !visited {
+				previous[edge.To] = edge
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	return paths
+}
+
+// A utility function that reports whether the principal's identity policies and permission boundary allow an action.
+// Every resource belongs to the principal's account. Statements whose conditions cannot be evaluated are recorded in
+// the principal's errors.
+func (p *privescPrincipal) allows(action string, resource string, context map[string][]string) bool {
+	requestContext := map[string][]string{"aws:resourceaccount": {policy.ARNAccount(p.arn)}}
+	for key, values := range context {
+		requestContext[key] = values
+	}
+	result := policy.Evaluate(policy.Request{Principal: p.arn, Action: action, Resource: resource, Context: requestContext}, policy.Policies{
+		Identity:            p.policies.Identity,
+		PermissionsBoundary: p.policies.PermissionsBoundary,
+	})
+	p.addErrors(result.Errors)
+	return result.Decision == policy.DecisionAllow
+}
+
+// A utility function that reports whether the role's trust policy allows a principal or service to assume it. Services
+// are evaluated as principals of the role's account. Statements whose conditions cannot be evaluated are recorded in the
+// role's errors.
+func (p *privescPrincipal) trusts(principal string) bool {
+	if p.trust == nil {
+		return false
+	}
+	requestContext := map[string][]string{}
+	if policy.ARNAccount(principal) == "" {
+		requestContext["aws:principalaccount"] = []string{policy.ARNAccount(p.arn)}
+	}
+	result := policy.Evaluate(policy.Request{Principal: principal, Action: "sts:AssumeRole", Resource: p.arn, Context: requestContext}, policy.Policies{
+		Resource: &policy.NamedDocument{Name: p.arn, Document: p.trust},
+	})
+	p.addErrors(result.Errors)
+	return result.Decision == policy.DecisionAllow
+}
+
+// A utility function that records the errors of an evaluation that the principal has not recorded yet, as the same
+// statement is evaluated for many techniques.
+func (p *privescPrincipal) addErrors(errs []string) {
+	if p.reportedErrors == nil {
+		p.reportedErrors = map[string]bool{}
+	}
+	for _, err := range errs {
+		if !p.reportedErrors[err] {
+			p.reportedErrors[err] = true
+			p.errors = append(p.errors, err)
+		}
+	}
+}
+
+// A utility function that reports whether the role's trust policy names a principal by its ARN, in which case the
+// principal does not need an identity policy allowing it to assume the role.
+func (p *privescPrincipal) trustNamesPrincipal(principal string) bool {
+	if p.trust == nil {
+		return false
+	}
+	for _, statement := range p.trust.Statement {
+		if !statement.IsAllow() || !statement.MatchesAction("sts:AssumeRole") {
+			continue
+		}
+		for _, value := range statement.Principal["AWS"] {
+			if value == principal {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// A utility function that returns the values sorted and without duplicates.
+func distinctStrings(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -3,6 +3,7 @@ package iam
 import (
 	"time"
 
+	"github.com/Method-Security/methodaws/internal/iam/policy"
 	"github.com/aws/aws-sdk-go-v2/service/iam/types"
 )
 
@@ -49,9 +50,45 @@ type DecodedRole struct {
 
 // RoleResource is a struct that contains the role details, attached policies, and inline policies for an IAM role. This
 // struct is used to represent the output of the `methodaws iam role` subcommand, providing the most holistic information
-// possible about all of the policies that a Role has available to it.
+// possible about all of the policies that a Role has available to it. EffectivePermissions summarizes what those
+// policies allow, grouped by service, after applying the role's permission boundary, and TrustedPrincipals classifies
+// the principals the role's trust policy allows to assume it.
 type RoleResource struct {
-	Role                 DecodedRole     `json:"role" yaml:"role"`
+	Role                 DecodedRole              `json:"role" yaml:"role"`
+	AttachedPoliciesArns []string                 `json:"attached_policies_arns" yaml:"attached_policies_arns"`
+	InlinePolicies       []*InlinePolicy          `json:"inline_policies" yaml:"inline_policies"`
+	EffectivePermissions policy.PermissionSummary `json:"effective_permissions" yaml:"effective_permissions"`
+	TrustedPrincipals    []TrustedPrincipal       `json:"trusted_principals" yaml:"trusted_principals"`
+}
+
+// AccessKeyResource is a struct that contains an access key's metadata alongside when, where and for which service it
+// was last used. The last used fields are empty for keys that have never been used.
+type AccessKeyResource struct {
+	AccessKey       types.AccessKeyMetadata `json:"access_key" yaml:"access_key"`
+	LastUsedDate    *time.Time              `json:"last_used_date" yaml:"last_used_date"`
+	LastUsedService *string                 `json:"last_used_service" yaml:"last_used_service"`
+	LastUsedRegion  *string                 `json:"last_used_region" yaml:"last_used_region"`
+}
+
+// UserResource is a struct that contains the user details, attached policies, inline policies, group memberships and
+// credentials for an IAM user. The user's permission boundary is included in the user details, and the boundary policy
+// itself is included in the report's policies alongside the attached policies. A user without a console password has
+// no LoginProfile.
+type UserResource struct {
+	User                       types.User                                `json:"user" yaml:"user"`
+	AttachedPoliciesArns       []string                                  `json:"attached_policies_arns" yaml:"attached_policies_arns"`
+	InlinePolicies             []*InlinePolicy                           `json:"inline_policies" yaml:"inline_policies"`
+	Groups                     []string                                  `json:"groups" yaml:"groups"`
+	AccessKeys                 []AccessKeyResource                       `json:"access_keys" yaml:"access_keys"`
+	MFADevices                 []types.MFADevice                         `json:"mfa_devices" yaml:"mfa_devices"`
+	LoginProfile               *types.LoginProfile                       `json:"login_profile" yaml:"login_profile"`
+	SSHPublicKeys              []types.SSHPublicKeyMetadata              `json:"ssh_public_keys" yaml:"ssh_public_keys"`
+	ServiceSpecificCredentials []types.ServiceSpecificCredentialMetadata `json:"service_specific_credentials" yaml:"service_specific_credentials"`
+}
+
+// GroupResource is a struct that contains the group details, attached policies, and inline policies for an IAM group.
+type GroupResource struct {
+	Group                types.Group     `json:"group" yaml:"group"`
 	AttachedPoliciesArns []string        `json:"attached_policies_arns" yaml:"attached_policies_arns"`
 	InlinePolicies       []*InlinePolicy `json:"inline_policies" yaml:"inline_policies"`
 }
@@ -60,8 +97,10 @@ type RoleResource struct {
 // represent the output of the `methodaws iam` subcommand, easing data integration and providing a more holistic view of
 // all of the IAM roles and policies that are available to the current AWS account.
 type AWSResources struct {
-	Roles    []RoleResource `json:"roles" yaml:"roles"`
-	Policies PolicyReport   `json:"policy_report" yaml:"policy_report"`
+	Roles    []RoleResource  `json:"roles" yaml:"roles"`
+	Users    []UserResource  `json:"users" yaml:"users"`
+	Groups   []GroupResource `json:"groups" yaml:"groups"`
+	Policies PolicyReport    `json:"policy_report" yaml:"policy_report"`
 }
 
 // AWSResourceReport is a struct that contains The Resources and Errors.. This struct is used to
@@ -3,6 +3,7 @@ package iam
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/Method-Security/methodaws/internal/sts"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -37,7 +38,8 @@ func EnumerateIamRoles(ctx context.Context, cfg aws.Config) (*AWSResourceReport,
 	}
 
 	for _, role := range roles {
-		roleResource, attachedPolicies, err := EnrichRoleWithPolicies(ctx, cfg, &role)
+		roleResource, attachedPolicies, errs, err := EnrichRoleWithPolicies(ctx, cfg, &role)
+		report.Errors = append(report.Errors, errs...)
 		if err != nil {
 			report.Errors = append(report.Errors, err.Error())
 			continue
@@ -59,25 +61,49 @@ func EnumerateIamRoles(ctx context.Context, cfg aws.Config) (*AWSResourceReport,
 }
 
 // EnrichRoleWithPolicies retrieves the attached and inline policies for a given IAM role. It returns a RoleResource struct
-// that contains the role, any attached policies, and any inline policies. It also returns a slice of PolicyResource structs
-// that contain the attached policies for the role.
-func EnrichRoleWithPolicies(ctx context.Context, cfg aws.Config, role *types.Role) (RoleResource, []PolicyResource, error) {
+// that contains the role, any attached policies, any inline policies, the role's effective permissions after applying
+// its permission boundary, and the principals its trust policy trusts. It also returns a slice of PolicyResource structs
+// that contain the attached policies and permission boundary for the role, and any non-fatal errors that occurred. An
+// error is returned when the role itself cannot be enriched.
+func EnrichRoleWithPolicies(ctx context.Context, cfg aws.Config, role *types.Role) (RoleResource, []PolicyResource, []string, error) {
+	client := iam.NewFromConfig(cfg)
+	roleName := aws.ToString(role.RoleName)
+	errs := []string{}
+
+	// ListRoles omits the permission boundary, last use and tags, which GetRole includes, so the boundary is unknown
+	// when the role details cannot be retrieved
+	boundaryUnknown := false
+	roleDetails, err := GetRoleDetails(ctx, cfg, roleName)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("Error getting role %s: %v", roleName, err))
+		boundaryUnknown = true
+	} else if roleDetails != nil {
+		role = roleDetails
+	}
 
 	decodedRole, err := decodeRole(role)
 	if err != nil {
-		return RoleResource{}, nil, err
+		return RoleResource{}, nil, errs, err
 	}
 
 	roleResource := RoleResource{
 		Role:                 *decodedRole,
 		AttachedPoliciesArns: []string{},
 		InlinePolicies:       []*InlinePolicy{},
+		TrustedPrincipals:    []TrustedPrincipal{},
+	}
+	roleTrust, err := AnalyzeRoleTrust(role)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("Error analyzing trust policy of role %s: %v", roleName, err))
+	} else {
+		roleResource.TrustedPrincipals = roleTrust.TrustedPrincipals
 	}
 
 	policyReport := GetAttachedPoliciesForRole(ctx, cfg, *role.RoleName)
 	if policyReport == nil {
-		return roleResource, nil, errors.New("failed to get attached policies for role")
+		return roleResource, nil, errs, errors.New("failed to get attached policies for role")
 	}
+	errs = append(errs, policyReport.Errors...)
 
 	for _, policy := range policyReport.Policies {
 		roleResource.AttachedPoliciesArns = append(roleResource.AttachedPoliciesArns, *policy.Policy.Arn)
@@ -85,26 +111,35 @@ func EnrichRoleWithPolicies(ctx context.Context, cfg aws.Config, role *types.Rol
 
 	inlinePolicies, err := GetInlinePoliciesForRole(ctx, cfg, *role.RoleName)
 	if err != nil {
-		return roleResource, nil, err
+		return roleResource, nil, errs, err
 	}
 
 	for _, inlinePolicy := range inlinePolicies {
-		decoded, err := decodeDocument(inlinePolicy.PolicyDocument)
+		decoded, err := decodeInlinePolicy(inlinePolicy.PolicyName, inlinePolicy.PolicyDocument)
 		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error decoding inline policy %s/%s: %v", aws.ToString(role.Arn), aws.ToString(inlinePolicy.PolicyName), err))
 			continue
 		}
-		minified, err := minifyJSON(*decoded)
-		if err != nil {
-			continue
+		roleResource.InlinePolicies = append(roleResource.InlinePolicies, decoded)
+	}
+
+	policies := policyReport.Policies
+	var boundary *PolicyResource
+	if role.PermissionsBoundary != nil && role.PermissionsBoundary.PermissionsBoundaryArn != nil {
+		boundaryPolicies, boundaryErrors := getPolicyResources(ctx, client, []string{*role.PermissionsBoundary.PermissionsBoundaryArn})
+		errs = append(errs, boundaryErrors...)
+		if len(boundaryPolicies) == 1 {
+			boundary = &boundaryPolicies[0]
+			policies = append(policies, *boundary)
+		} else {
+			boundaryUnknown = true
 		}
-
-		roleResource.InlinePolicies = append(roleResource.InlinePolicies, &InlinePolicy{
-			PolicyName: *inlinePolicy.PolicyName,
-			Policy:     *minified,
-		})
 	}
+	effectivePermissions, effectiveErrors := EffectivePermissions(aws.ToString(role.Arn), policyReport.Policies, roleResource.InlinePolicies, boundary, boundaryUnknown)
+	roleResource.EffectivePermissions = effectivePermissions
+	errs = append(errs, effectiveErrors...)
 
-	return roleResource, policyReport.Policies, nil
+	return roleResource, policies, errs, nil
 }
 
 // GetRoleDetails uses the AWS SDK to retrieve and return a Role for the provided role name.
@@ -0,0 +1,276 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Method-Security/methodaws/internal/iam/policy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// SimulateOptions are the optional inputs of a simulation. Context holds condition key values given as key=value,
+// where a key given more than once has multiple values. ResourceAccount is the account that owns the resource, which
+// sets aws:ResourceAccount and is needed to evaluate cross-account requests on resources whose ARN has no account, such
+// as S3 buckets and objects. ResourcePolicyFile and SCPFiles are paths to JSON policy documents to evaluate alongside
+// the principal's own policies.
+type SimulateOptions struct {
+	Context            []string
+	ResourceAccount    string
+	ResourcePolicyFile string
+	SCPFiles           []string
+}
+
+// SimulationReport is a struct that contains the outcome of simulating a request offline, the policies that were
+// evaluated and any non-fatal errors that occurred. This struct is used to represent the output of the
+// `methodaws iam simulate` subcommand.
+type SimulationReport struct {
+	Principal         string                    `json:"principal" yaml:"principal"`
+	Action            string                    `json:"action" yaml:"action"`
+	Resource          string                    `json:"resource" yaml:"resource"`
+	Context           map[string][]string       `json:"context" yaml:"context"`
+	EvaluatedPolicies []string                  `json:"evaluated_policies" yaml:"evaluated_policies"`
+	Decision          policy.Decision           `json:"decision" yaml:"decision"`
+	Reason            string                    `json:"reason" yaml:"reason"`
+	CrossAccount      *bool                     `json:"cross_account" yaml:"cross_account"`
+	MatchedStatements []policy.MatchedStatement `json:"matched_statements" yaml:"matched_statements"`
+	Errors            []string                  `json:"errors" yaml:"errors"`
+}
+
+// Simulate evaluates whether a principal is allowed to perform an action on a resource with the offline policy
+// engine. The principal's identity policies and permission boundary are retrieved from IAM: a role's attached and
+// inline policies, or a user's attached and inline policies along with those of its groups. Assumed role session ARNs
+// are evaluated as their role. The resource policy and service control policies are read from the files in options. An
+// error is returned when the principal cannot be retrieved. When some of its policies cannot be retrieved or parsed,
+// a decision other than an explicit deny is reported as inconclusive, as the missing policies may change it.
+func Simulate(ctx context.Context, cfg aws.Config, principal string, action string, resource string, options SimulateOptions) (*SimulationReport, error) {
+	report := SimulationReport{
+		Principal:         principal,
+		Action:            action,
+		Resource:          resource,
+		Context:           map[string][]string{},
+		EvaluatedPolicies: []string{},
+		MatchedStatements: []policy.MatchedStatement{},
+		Errors:            []string{},
+	}
+
+	for _, value := range options.Context {
+		key, contextValue, found := strings.Cut(value, "=")
+		if !found || key == "" {
+			return &report, fmt.Errorf("invalid context value %s, expected key=value", value)
+		}
+		key = strings.ToLower(key)
+		report.Context[key] = append(report.Context[key], contextValue)
+	}
+	if options.ResourceAccount != "" {
+		report.Context["aws:resourceaccount"] = []string{options.ResourceAccount}
+	}
+
+	policies, errs, err := GetPrincipalPolicies(ctx, cfg, principal)
+	if err != nil {
+		return &report, err
+	}
+	report.Errors = append(report.Errors, errs...)
+
+	if options.ResourcePolicyFile != "" {
+		document, err := policy.ParseFile(options.ResourcePolicyFile)
+		if err != nil {
+			return &report, err
+		}
+		policies.Resource = &policy.NamedDocument{Name: options.ResourcePolicyFile, Document: document}
+	}
+	for _, path := range options.SCPFiles {
+		document, err := policy.ParseFile(path)
+		if err != nil {
+			return &report, err
+		}
+		policies.ServiceControlPolicies = append(policies.ServiceControlPolicies, policy.NamedDocument{Name: path, Document: document})
+	}
+
+	for _, document := range policies.Identity {
+		report.EvaluatedPolicies = append(report.EvaluatedPolicies, document.Name)
+	}
+	for _, document := range []*policy.NamedDocument{policies.PermissionsBoundary, policies.Resource} {
+		if document != nil {
+			report.EvaluatedPolicies = append(report.EvaluatedPolicies, document.Name)
+		}
+	}
+	for _, document := range policies.ServiceControlPolicies {
+		report.EvaluatedPolicies = append(report.EvaluatedPolicies, document.Name)
+	}
+
+	result := policy.Evaluate(policy.Request{
+		Principal: principal,
+		Action:    action,
+		Resource:  resource,
+		Context:   report.Context,
+	}, policies)
+	report.Decision = result.Decision
+	report.Reason = result.Reason
+	report.CrossAccount = result.CrossAccount
+	report.MatchedStatements = result.MatchedStatements
+	report.Errors = append(report.Errors, result.Errors...)
+	if len(errs) > 0 && report.Decision != policy.DecisionExplicitDeny {
+		report.Decision = policy.DecisionInconclusive
+		report.Reason = "Some of the principal's policies could not be retrieved or parsed, so the decision is unknown"
+	}
+
+	return &report, nil
+}
+
+// GetPrincipalPolicies retrieves and parses the identity policies and permission boundary of an IAM role or user,
+// identified by its ARN. Managed policies are named by their ARN and inline policies by the principal's ARN and the
+// policy name. An error is returned when the principal is not an IAM role, user or assumed role, or cannot be retrieved.
+// A policy that cannot be retrieved or parsed is skipped and returned as a non-fatal error, in which case the policies
+// are incomplete.
+func GetPrincipalPolicies(ctx context.Context, cfg aws.Config, principalArn string) (policy.Policies, []string, error) {
+	client := iam.NewFromConfig(cfg)
+	policies := policy.Policies{Identity: []policy.NamedDocument{}}
+	errs := []string{}
+
+	principalType, principalName := principalFromArn(principalArn)
+	var managedPolicies []PolicyResource
+	var boundaryArn *string
+	var inlineDocuments []namedInlineDocument
+
+	switch principalType {
+	case "role":
+		role, err := GetRoleDetails(ctx, cfg, principalName)
+		if err != nil {
+			return policies, errs, fmt.Errorf("error getting role %s: %v", principalName, err)
+		}
+		if role.PermissionsBoundary != nil {
+			boundaryArn = role.PermissionsBoundary.PermissionsBoundaryArn
+		}
+
+		attached := GetAttachedPoliciesForRole(ctx, cfg, principalName)
+		errs = append(errs, attached.Errors...)
+		managedPolicies = append(managedPolicies, attached.Policies...)
+
+		inlinePolicies, err := GetInlinePoliciesForRole(ctx, cfg, principalName)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error getting inline policies for role %s: %v", principalName, err))
+		}
+		for _, inlinePolicy := range inlinePolicies {
+			inlineDocuments = append(inlineDocuments, namedInlineDocument{principalArn, inlinePolicy.PolicyName, inlinePolicy.PolicyDocument})
+		}
+	case "user":
+		userOutput, err := client.GetUser(ctx, &iam.GetUserInput{UserName: aws.String(principalName)})
+		if err != nil {
+			return policies, errs, fmt.Errorf("error getting user %s: %v", principalName, err)
+		}
+		if userOutput.User != nil && userOutput.User.PermissionsBoundary != nil {
+			boundaryArn = userOutput.User.PermissionsBoundary.PermissionsBoundaryArn
+		}
+
+		attached := GetAttachedPoliciesForUser(ctx, cfg, principalName)
+		errs = append(errs, attached.Errors...)
+		managedPolicies = append(managedPolicies, attached.Policies...)
+
+		inlinePolicies, err := GetInlinePoliciesForUser(ctx, cfg, principalName)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error getting inline policies for user %s: %v", principalName, err))
+		}
+		for _, inlinePolicy := range inlinePolicies {
+			inlineDocuments = append(inlineDocuments, namedInlineDocument{principalArn, inlinePolicy.PolicyName, inlinePolicy.PolicyDocument})
+		}
+
+		groupsPaginator := iam.NewListGroupsForUserPaginator(client, &iam.ListGroupsForUserInput{UserName: aws.String(principalName)})
+		for groupsPaginator.HasMorePages() {
+			page, err := groupsPaginator.NextPage(ctx)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("Error listing groups for user %s: %v", principalName, err))
+				break
+			}
+			for _, group := range page.Groups {
+				groupName := aws.ToString(group.GroupName)
+				groupAttached := GetAttachedPoliciesForGroup(ctx, cfg, groupName)
+				errs = append(errs, groupAttached.Errors...)
+				managedPolicies = append(managedPolicies, groupAttached.Policies...)
+
+				groupInlinePolicies, err := GetInlinePoliciesForGroup(ctx, cfg, groupName)
+				if err != nil {
+					errs = append(errs, fmt.Sprintf("Error getting inline policies for group %s: %v", groupName, err))
+				}
+				for _, inlinePolicy := range groupInlinePolicies {
+					inlineDocuments = append(inlineDocuments, namedInlineDocument{aws.ToString(group.Arn), inlinePolicy.PolicyName, inlinePolicy.PolicyDocument})
+				}
+			}
+		}
+	default:
+		return policies, errs, fmt.Errorf("unsupported principal %s, expected an IAM role, user or assumed role ARN", principalArn)
+	}
+
+	seen := map[string]bool{}
+	for _, managedPolicy := range managedPolicies {
+		if seen[aws.ToString(managedPolicy.Policy.Arn)] {
+			continue
+		}
+		seen[aws.ToString(managedPolicy.Policy.Arn)] = true
+		document, err := policy.Parse(aws.ToString(managedPolicy.PolicyVersion.Document))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error parsing policy %s: %v", aws.ToString(managedPolicy.Policy.Arn), err))
+			continue
+		}
+		policies.Identity = append(policies.Identity, policy.NamedDocument{Name: aws.ToString(managedPolicy.Policy.Arn), Document: document})
+	}
+
+	for _, inlineDocument := range inlineDocuments {
+		name := fmt.Sprintf("%s/%s", inlineDocument.owner, aws.ToString(inlineDocument.name))
+		decoded, err := decodeDocument(inlineDocument.document)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error decoding inline policy %s: %v", name, err))
+			continue
+		}
+		document, err := policy.Parse(*decoded)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error parsing inline policy %s: %v", name, err))
+			continue
+		}
+		policies.Identity = append(policies.Identity, policy.NamedDocument{Name: name, Document: document})
+	}
+
+	if boundaryArn != nil {
+		boundaryPolicies, boundaryErrors := getPolicyResources(ctx, client, []string{*boundaryArn})
+		errs = append(errs, boundaryErrors...)
+		for _, boundaryPolicy := range boundaryPolicies {
+			document, err := policy.Parse(aws.ToString(boundaryPolicy.PolicyVersion.Document))
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("Error parsing permission boundary %s: %v", *boundaryArn, err))
+				continue
+			}
+			policies.PermissionsBoundary = &policy.NamedDocument{Name: *boundaryArn, Document: document}
+		}
+	}
+
+	return policies, errs, nil
+}
+
+// namedInlineDocument is an inline policy document alongside the ARN of the principal or group that owns it.
+type namedInlineDocument struct {
+	owner    string
+	name     *string
+	document *string
+}
+
+// A utility function that returns the type ("role" or "user") and name of the IAM principal identified by an ARN. An
+// assumed role session ARN identifies its role. Paths are stripped from the name.
+func principalFromArn(principalArn string) (string, string) {
+	parts := strings.SplitN(principalArn, ":", 6)
+	if len(parts) != 6 {
+		return "", ""
+	}
+	resourceType, resource, found := strings.Cut(parts[5], "/")
+	if !found {
+		return "", ""
+	}
+	switch {
+	case parts[2] == "iam" && (resourceType == "role" || resourceType == "user"):
+		return resourceType, resource[strings.LastIndex(resource, "/")+1:]
+	case parts[2] == "sts" && resourceType == "assumed-role":
+		roleName, _, _ := strings.Cut(resource, "/")
+		return "role", roleName
+	}
+	return "", ""
+}
@@ -0,0 +1,325 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Method-Security/methodaws/internal/iam/policy"
+	"github.com/Method-Security/methodaws/internal/sts"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// TrustedPrincipalType classifies a principal that a role's trust policy allows to assume the role.
+type TrustedPrincipalType string
+
+const (
+	TrustedPrincipalSameAccount   TrustedPrincipalType = "SAME_ACCOUNT"
+	TrustedPrincipalOtherAccount  TrustedPrincipalType = "OTHER_ACCOUNT"
+	TrustedPrincipalService       TrustedPrincipalType = "SERVICE"
+	TrustedPrincipalFederatedOIDC TrustedPrincipalType = "FEDERATED_OIDC"
+	TrustedPrincipalFederatedSAML TrustedPrincipalType = "FEDERATED_SAML"
+	TrustedPrincipalCanonicalUser TrustedPrincipalType = "CANONICAL_USER"
+	TrustedPrincipalWildcard      TrustedPrincipalType = "WILDCARD"
+	// TrustedPrincipalUnresolved is a principal whose account cannot be derived, such as the unique ID that replaces the
+	// ARN of a deleted role or user, or a principal of an unknown type
+	TrustedPrincipalUnresolved TrustedPrincipalType = "UNRESOLVED"
+)
+
+// TrustFinding is a weakness in the way a role's trust policy trusts a principal.
+type TrustFinding string
+
+const (
+	// TrustFindingMissingExternalID means that another account is trusted without requiring sts:ExternalId, leaving
+	// the role open to the confused deputy problem when the account belongs to a third party
+	TrustFindingMissingExternalID TrustFinding = "MISSING_EXTERNAL_ID"
+	// TrustFindingMissingOIDCSubject means that an OIDC provider is trusted without a condition on the token's subject,
+	// so that any identity of the provider, e.g. any GitHub Actions workflow, can assume the role
+	TrustFindingMissingOIDCSubject TrustFinding = "MISSING_OIDC_SUBJECT"
+	// TrustFindingBroadOIDCSubject means that the subject condition of an OIDC provider has a wildcard in its first
+	// segment, e.g. the GitHub Actions subject repo:*, so that identities of any owner can assume the role
+	TrustFindingBroadOIDCSubject TrustFinding = "BROAD_OIDC_SUBJECT"
+	// TrustFindingUnconditionedWildcard means that every principal is trusted without any condition
+	TrustFindingUnconditionedWildcard TrustFinding = "UNCONDITIONED_WILDCARD"
+	// TrustFindingUnresolvedPrincipal means that a trusted principal cannot be resolved to an account, usually because
+	// it was deleted, so the trust policy should be reviewed
+	TrustFindingUnresolvedPrincipal TrustFinding = "UNRESOLVED_PRINCIPAL"
+)
+
+// cognitoIdentityProvider is the federated principal of Amazon Cognito identity pools.
+const cognitoIdentityProvider = "cognito-identity.amazonaws.com"
+
+// TrustedPrincipal is a principal that a role's trust policy allows to assume the role, alongside the actions it is
+// allowed, the condition keys it must satisfy, and the findings that apply to it. AccountID is set for AWS principals.
+type TrustedPrincipal struct {
+	Principal     string               `json:"principal" yaml:"principal"`
+	Type          TrustedPrincipalType `json:"type" yaml:"type"`
+	AccountID     string               `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	Actions       []string             `json:"actions" yaml:"actions"`
+	ConditionKeys []string             `json:"condition_keys" yaml:"condition_keys"`
+	Findings      []TrustFinding       `json:"findings" yaml:"findings"`
+}
+
+// RoleTrust is the analysis of a single role's trust policy.
+type RoleTrust struct {
+	RoleArn           string             `json:"role_arn" yaml:"role_arn"`
+	RoleName          string             `json:"role_name" yaml:"role_name"`
+	TrustedPrincipals []TrustedPrincipal `json:"trusted_principals" yaml:"trusted_principals"`
+}
+
+// TrustEdge is an edge of the trust graph, from the account, service, identity provider or wildcard that is trusted
+// to the role that trusts it.
+type TrustEdge struct {
+	Source     string               `json:"source" yaml:"source"`
+	SourceType TrustedPrincipalType `json:"source_type" yaml:"source_type"`
+	Role       string               `json:"role" yaml:"role"`
+	Findings   []TrustFinding       `json:"findings" yaml:"findings"`
+}
+
+// TrustReport is a struct that contains the trust policy analysis of every role in the account, the trust graph built
+// from it, and any non-fatal errors that occurred. This struct is used to represent the output of the
+// `methodaws iam trust` subcommand.
+type TrustReport struct {
+	AccountID string      `json:"account_id" yaml:"account_id"`
+	Roles     []RoleTrust `json:"roles" yaml:"roles"`
+	Graph     []TrustEdge `json:"graph" yaml:"graph"`
+	Errors    []string    `json:"errors" yaml:"errors"`
+}
+
+// AnalyzeTrust parses the trust policy of every role in the account, classifying the principals each role trusts and
+// flagging the weaknesses described by TrustFinding. It returns a TrustReport that also contains the trust graph, with
+// one edge from every trusted account, service, identity provider or wildcard to each role that trusts it.
+func AnalyzeTrust(ctx context.Context, cfg aws.Config) (*TrustReport, error) {
+	client := iam.NewFromConfig(cfg)
+	report := TrustReport{
+		Roles:  []RoleTrust{},
+		Graph:  []TrustEdge{},
+		Errors: []string{},
+	}
+
+	accountID, err := sts.GetAccountID(ctx, cfg)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return &report, nil
+	}
+	report.AccountID = aws.ToString(accountID)
+
+	roles, err := GetAllRoles(ctx, client)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return &report, nil
+	}
+
+	edges := map[string]*TrustEdge{}
+	for _, role := range roles {
+		roleTrust, err := AnalyzeRoleTrust(&role)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("Error analyzing trust policy of role %s: %v", aws.ToString(role.RoleName), err))
+			continue
+		}
+		report.Roles = append(report.Roles, roleTrust)
+
+		for _, principal := range roleTrust.TrustedPrincipals {
+			source := principal.Principal
+			if principal.AccountID != "" {
+				source = principal.AccountID
+			}
+			key := source + "|" + roleTrust.RoleArn
+			if _, ok := edges[key]; !ok {
+				edges[key] = &TrustEdge{Source: source, SourceType: principal.Type, Role: roleTrust.RoleArn, Findings: []TrustFinding{}}
+			}
+			edges[key].Findings = append(edges[key].Findings, principal.Findings...)
+		}
+	}
+
+	for _, edge := range edges {
+		edge.Findings = distinctFindings(edge.Findings)
+		report.Graph = append(report.Graph, *edge)
+	}
+	sort.Slice(report.Graph, func(i, j int) bool {
+		if report.Graph[i].Source != report.Graph[j].Source {
+			return report.Graph[i].Source < report.Graph[j].Source
+		}
+		return report.Graph[i].Role < report.Graph[j].Role
+	})
+
+	return &report, nil
+}
+
+// AnalyzeRoleTrust parses a role's trust policy and classifies every principal it allows to assume the role. A principal
+// named in several statements is reported once per statement.
+func AnalyzeRoleTrust(role *types.Role) (RoleTrust, error) {
+	roleArn := aws.ToString(role.Arn)
+	roleTrust := RoleTrust{
+		RoleArn:           roleArn,
+		RoleName:          aws.ToString(role.RoleName),
+		TrustedPrincipals: []TrustedPrincipal{},
+	}
+	if role.AssumeRolePolicyDocument == nil {
+		return roleTrust, nil
+	}
+
+	decoded, err := decodeDocument(role.AssumeRolePolicyDocument)
+	if err != nil {
+		return roleTrust, err
+	}
+	document, err := policy.Parse(*decoded)
+	if err != nil {
+		return roleTrust, err
+	}
+	roleTrust.TrustedPrincipals = trustedPrincipals(document, policy.ARNAccount(roleArn))
+
+	return roleTrust, nil
+}
+
+// A utility function that classifies the principals trusted by the Allow statements of a trust policy. A statement
+// that uses NotPrincipal trusts every principal but the ones it names, so it is classified as a wildcard.
+func trustedPrincipals(document *policy.Document, roleAccount string) []TrustedPrincipal {
+	principals := []TrustedPrincipal{}
+	for _, statement := range document.Statement {
+		if !statement.IsAllow() {
+			continue
+		}
+
+		conditionKeys := []string{}
+		for _, conditions := range statement.Condition {
+			for key := range conditions {
+				conditionKeys = append(conditionKeys, strings.ToLower(key))
+			}
+		}
+		sort.Strings(conditionKeys)
+
+		elements := statement.Principal
+		if statement.NotPrincipal != nil {
+			elements = policy.Principal{"*": {"*"}}
+		}
+		for principalType, values := range elements {
+			for _, value := range values {
+				principal := TrustedPrincipal{
+					Principal:     value,
+					Type:          classifyTrustedPrincipal(principalType, value, roleAccount),
+					Actions:       statement.Action,
+					ConditionKeys: conditionKeys,
+					Findings:      []TrustFinding{},
+				}
+				if principal.Actions == nil {
+					principal.Actions = []string{}
+				}
+				if principalType == "AWS" && value != "*" {
+					principal.AccountID = policy.PrincipalAccount(value)
+				}
+				principal.Findings = trustFindings(principal, statement)
+				principals = append(principals, principal)
+			}
+		}
+	}
+
+	sort.SliceStable(principals, func(i, j int) bool {
+		return principals[i].Principal < principals[j].Principal
+	})
+	return principals
+}
+
+// A utility function that classifies a trusted principal by its type in the principal element and, for AWS
+// principals, by whether it belongs to the role's own account. AWS principals without an account and principals of
+// an unknown type are unresolved.
+func classifyTrustedPrincipal(principalType string, value string, roleAccount string) TrustedPrincipalType {
+	if value == "*" {
+		return TrustedPrincipalWildcard
+	}
+	switch principalType {
+	case "Service":
+		return TrustedPrincipalService
+	case "Federated":
+		if strings.Contains(value, ":saml-provider/") {
+			return TrustedPrincipalFederatedSAML
+		}
+		return TrustedPrincipalFederatedOIDC
+	case "CanonicalUser":
+		return TrustedPrincipalCanonicalUser
+	case "AWS":
+		switch account := policy.PrincipalAccount(value); account {
+		case "":
+			return TrustedPrincipalUnresolved
+		case roleAccount:
+			return TrustedPrincipalSameAccount
+		default:
+			return TrustedPrincipalOtherAccount
+		}
+	}
+	return TrustedPrincipalUnresolved
+}
+
+// A utility function that flags the weaknesses in the way a statement trusts a principal.
+func trustFindings(principal TrustedPrincipal, statement policy.Statement) []TrustFinding {
+	findings := []TrustFinding{}
+	switch principal.Type {
+	case TrustedPrincipalOtherAccount:
+		if len(conditionValues(statement, "sts:externalid")) == 0 {
+			findings = append(findings, TrustFindingMissingExternalID)
+		}
+	case TrustedPrincipalFederatedOIDC:
+		// OIDC condition keys are prefixed with the provider's host, e.g. token.actions.githubusercontent.com:sub
+		provider := principal.Principal
+		if _, host, found := strings.Cut(provider, ":oidc-provider/"); found {
+			provider = host
+		}
+		subjectKey := strings.ToLower(provider) + ":sub"
+		if provider == cognitoIdentityProvider {
+			// Cognito identity pools are scoped by the pool ID in the audience instead
+			subjectKey = cognitoIdentityProvider + ":aud"
+		}
+		subjects := conditionValues(statement, subjectKey)
+		if len(subjects) == 0 {
+			findings = append(findings, TrustFindingMissingOIDCSubject)
+		}
+		for _, subject := range subjects {
+			segment, _, _ := strings.Cut(subject, "/")
+			if strings.ContainsAny(segment, "*?") {
+				findings = append(findings, TrustFindingBroadOIDCSubject)
+				break
+			}
+		}
+	case TrustedPrincipalWildcard:
+		if len(statement.Condition) == 0 {
+			findings = append(findings, TrustFindingUnconditionedWildcard)
+		}
+	case TrustedPrincipalUnresolved:
+		findings = append(findings, TrustFindingUnresolvedPrincipal)
+	}
+	return findings
+}
+
+// A utility function that returns the values of every condition on a key in a statement, whatever its operator.
+func conditionValues(statement policy.Statement, key string) []string {
+	values := []string{}
+	for _, conditions := range statement.Condition {
+		for conditionKey, conditionValues := range conditions {
+			if strings.EqualFold(conditionKey, key) {
+				values = append(values, conditionValues...)
+			}
+		}
+	}
+	return values
+}
+
+// A utility function that returns the findings sorted and without duplicates.
+func distinctFindings(findings []TrustFinding) []TrustFinding {
+	unique := []TrustFinding{}
+	for _, finding := range findings {
+		duplicate := false
+		for _, existing := range unique {
+			duplicate = duplicate || existing == finding
+		}
+		if !duplicate {
+			unique = append(unique, finding)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool {
+		return unique[i] < unique[j]
+	})
+	return unique
+}
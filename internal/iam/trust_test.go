@@ -0,0 +1,182 @@
+package iam
+
+import (
+	"testing"
+
+	"github.com/Method-Security/methodaws/internal/iam/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRoleAccount  = "111111111111"
+	testGitHubOIDC   = "arn:aws:iam::111111111111:oidc-provider/token.actions.githubusercontent.com"
+	testSAMLProvider = "arn:aws:iam::111111111111:saml-provider/okta"
+)
+
+func TestTrustedPrincipals(t *testing.T) {
+	tests := []struct {
+		desc      string
+		giveTrust string
+		want      []TrustedPrincipal
+	}{
+		{
+			desc:      "same account root",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111111111111:root"},"Action":"sts:AssumeRole"}}`,
+			want: []TrustedPrincipal{{
+				Principal:     "arn:aws:iam::111111111111:root",
+				Type:          TrustedPrincipalSameAccount,
+				AccountID:     testRoleAccount,
+				Actions:       []string{"sts:AssumeRole"},
+				ConditionKeys: []string{},
+				Findings:      []TrustFinding{},
+			}},
+		},
+		{
+			desc:      "other account without external ID",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::222222222222:role/deployer"},"Action":"sts:AssumeRole"}}`,
+			want: []TrustedPrincipal{{
+				Principal:     "arn:aws:iam::222222222222:role/deployer",
+				Type:          TrustedPrincipalOtherAccount,
+				AccountID:     "222222222222",
+				Actions:       []string{"sts:AssumeRole"},
+				ConditionKeys: []string{},
+				Findings:      []TrustFinding{TrustFindingMissingExternalID},
+			}},
+		},
+		{
+			desc:      "other account ID with external ID",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"AWS":"222222222222"},"Action":"sts:AssumeRole","Condition":{"StringEquals":{"sts:ExternalId":"vendor-id"}}}}`,
+			want: []TrustedPrincipal{{
+				Principal:     "222222222222",
+				Type:          TrustedPrincipalOtherAccount,
+				AccountID:     "222222222222",
+				Actions:       []string{"sts:AssumeRole"},
+				ConditionKeys: []string{"sts:externalid"},
+				Findings:      []TrustFinding{},
+			}},
+		},
+		{
+			desc:      "unique ID of a deleted role",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"AWS":"AROAEXAMPLEUNIQUEID"},"Action":"sts:AssumeRole"}}`,
+			want: []TrustedPrincipal{{
+				Principal:     "AROAEXAMPLEUNIQUEID",
+				Type:          TrustedPrincipalUnresolved,
+				Actions:       []string{"sts:AssumeRole"},
+				ConditionKeys: []string{},
+				Findings:      []TrustFinding{TrustFindingUnresolvedPrincipal},
+			}},
+		},
+		{
+			desc:      "service",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"Service":"lambda.amazonaws.com"},"Action":"sts:AssumeRole"}}`,
+			want: []TrustedPrincipal{{
+				Principal:     "lambda.amazonaws.com",
+				Type:          TrustedPrincipalService,
+				Actions:       []string{"sts:AssumeRole"},
+				ConditionKeys: []string{},
+				Findings:      []TrustFinding{},
+			}},
+		},
+		{
+			desc:      "OIDC provider without subject",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"Federated":"` + testGitHubOIDC + `"},"Action":"sts:AssumeRoleWithWebIdentity","Condition":{"StringEquals":{"token.actions.githubusercontent.com:aud":"sts.amazonaws.com"}}}}`,
+			want: []TrustedPrincipal{{
+				Principal:     testGitHubOIDC,
+				Type:          TrustedPrincipalFederatedOIDC,
+				Actions:       []string{"sts:AssumeRoleWithWebIdentity"},
+				ConditionKeys: []string{"token.actions.githubusercontent.com:aud"},
+				Findings:      []TrustFinding{TrustFindingMissingOIDCSubject},
+			}},
+		},
+		{
+			desc:      "OIDC provider with a subject for any repository",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"Federated":"` + testGitHubOIDC + `"},"Action":"sts:AssumeRoleWithWebIdentity","Condition":{"StringLike":{"token.actions.githubusercontent.com:sub":"repo:*"}}}}`,
+			want: []TrustedPrincipal{{
+				Principal:     testGitHubOIDC,
+				Type:          TrustedPrincipalFederatedOIDC,
+				Actions:       []string{"sts:AssumeRoleWithWebIdentity"},
+				ConditionKeys: []string{"token.actions.githubusercontent.com:sub"},
+				Findings:      []TrustFinding{TrustFindingBroadOIDCSubject},
+			}},
+		},
+		{
+			desc:      "OIDC provider with a subject for a single repository",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"Federated":"` + testGitHubOIDC + `"},"Action":"sts:AssumeRoleWithWebIdentity","Condition":{"StringLike":{"token.actions.githubusercontent.com:sub":"repo:example/app:*"}}}}`,
+			want: []TrustedPrincipal{{
+				Principal:     testGitHubOIDC,
+				Type:          TrustedPrincipalFederatedOIDC,
+				Actions:       []string{"sts:AssumeRoleWithWebIdentity"},
+				ConditionKeys: []string{"token.actions.githubusercontent.com:sub"},
+				Findings:      []TrustFinding{},
+			}},
+		},
+		{
+			desc:      "cognito identity pool with audience",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"Federated":"cognito-identity.amazonaws.com"},"Action":"sts:AssumeRoleWithWebIdentity","Condition":{"StringEquals":{"cognito-identity.amazonaws.com:aud":"us-east-1:pool"}}}}`,
+			want: []TrustedPrincipal{{
+				Principal:     "cognito-identity.amazonaws.com",
+				Type:          TrustedPrincipalFederatedOIDC,
+				Actions:       []string{"sts:AssumeRoleWithWebIdentity"},
+				ConditionKeys: []string{"cognito-identity.amazonaws.com:aud"},
+				Findings:      []TrustFinding{},
+			}},
+		},
+		{
+			desc:      "cognito identity pool without audience",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"Federated":"cognito-identity.amazonaws.com"},"Action":"sts:AssumeRoleWithWebIdentity","Condition":{"ForAnyValue:StringLike":{"cognito-identity.amazonaws.com:amr":"authenticated"}}}}`,
+			want: []TrustedPrincipal{{
+				Principal:     "cognito-identity.amazonaws.com",
+				Type:          TrustedPrincipalFederatedOIDC,
+				Actions:       []string{"sts:AssumeRoleWithWebIdentity"},
+				ConditionKeys: []string{"cognito-identity.amazonaws.com:amr"},
+				Findings:      []TrustFinding{TrustFindingMissingOIDCSubject},
+			}},
+		},
+		{
+			desc:      "SAML provider",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":{"Federated":"` + testSAMLProvider + `"},"Action":"sts:AssumeRoleWithSAML"}}`,
+			want: []TrustedPrincipal{{
+				Principal:     testSAMLProvider,
+				Type:          TrustedPrincipalFederatedSAML,
+				Actions:       []string{"sts:AssumeRoleWithSAML"},
+				ConditionKeys: []string{},
+				Findings:      []TrustFinding{},
+			}},
+		},
+		{
+			desc:      "unconditioned wildcard",
+			giveTrust: `{"Statement":{"Effect":"Allow","Principal":"*","Action":"sts:AssumeRole"}}`,
+			want: []TrustedPrincipal{{
+				Principal:     "*",
+				Type:          TrustedPrincipalWildcard,
+				Actions:       []string{"sts:AssumeRole"},
+				ConditionKeys: []string{},
+				Findings:      []TrustFinding{TrustFindingUnconditionedWildcard},
+			}},
+		},
+		{
+			desc:      "not principal",
+			giveTrust: `{"Statement":{"Effect":"Allow","NotPrincipal":{"AWS":"arn:aws:iam::222222222222:root"},"Action":"sts:AssumeRole","Condition":{"StringEquals":{"aws:PrincipalOrgID":"o-example"}}}}`,
+			want: []TrustedPrincipal{{
+				Principal:     "*",
+				Type:          TrustedPrincipalWildcard,
+				Actions:       []string{"sts:AssumeRole"},
+				ConditionKeys: []string{"aws:principalorgid"},
+				Findings:      []TrustFinding{},
+			}},
+		},
+		{
+			desc:      "deny statement",
+			giveTrust: `{"Statement":{"Effect":"Deny","Principal":{"AWS":"arn:aws:iam::222222222222:root"},"Action":"sts:AssumeRole"}}`,
+			want:      []TrustedPrincipal{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			document, err := policy.Parse(tt.giveTrust)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, trustedPrincipals(document, testRoleAccount))
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package iam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Method-Security/methodaws/internal/sts"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+const (
+	// lastAccessedWorkers is the number of principals whose last accessed details are retrieved concurrently, kept
+	// low as the IAM API is throttled per account
+	lastAccessedWorkers = 8
+
+	// lastAccessedPollInterval and lastAccessedMaxPolls bound how long to wait for AWS to generate the last accessed
+	// details of a single principal
+	lastAccessedPollInterval = 2 * time.Second
+	lastAccessedMaxPolls     = 30
+)
+
+// UnusedPrincipalType is the kind of IAM principal whose access is analyzed.
+type UnusedPrincipalType string
+
+const (
+	UnusedPrincipalRole UnusedPrincipalType = "ROLE"
+	UnusedPrincipalUser UnusedPrincipalType = "USER"
+)
+
+// ServiceAccess is a service that a principal's policies grant access to, alongside when and where the principal last
+// accessed it. LastAuthenticated is empty for services that were never accessed within the IAM tracking period.
+type ServiceAccess struct {
+	ServiceName             string     `json:"service_name" yaml:"service_name"`
+	ServiceNamespace        string     `json:"service_namespace" yaml:"service_namespace"`
+	LastAuthenticated       *time.Time `json:"last_authenticated" yaml:"last_authenticated"`
+	LastAuthenticatedRegion *string    `json:"last_authenticated_region" yaml:"last_authenticated_region"`
+}
+
+// UnusedAccessPrincipal is the access analysis of a single IAM role or user. LastUsed is when a role was last assumed,
+// or when a user last signed in or used an access key. Unused is set when the principal was not used in the analyzed
+// number of days, or never used since being created more than that many days ago. NeverAccessedServices and
+// StaleServices are the services its policies grant that it never accessed, or did not access in that time.
+type UnusedAccessPrincipal struct {
+	Arn                   string              `json:"arn" yaml:"arn"`
+	Name                  string              `json:"name" yaml:"name"`
+	Type                  UnusedPrincipalType `json:"type" yaml:"type"`
+	CreateDate            *time.Time          `json:"create_date" yaml:"create_date"`
+	LastUsed              *time.Time          `json:"last_used" yaml:"last_used"`
+	LastUsedRegion        *string             `json:"last_used_region" yaml:"last_used_region"`
+	Unused                bool                `json:"unused" yaml:"unused"`
+	GrantedServices       int                 `json:"granted_services" yaml:"granted_services"`
+	NeverAccessedServices []ServiceAccess     `json:"never_accessed_services" yaml:"never_accessed_services"`
+	StaleServices         []ServiceAccess     `json:"stale_services" yaml:"stale_services"`
+}
+
+// UnusedAccessReport is a struct that contains the access analysis of every IAM role and user in the account and any
+// non-fatal errors that occurred. This struct is used to represent the output of the `methodaws iam unused` subcommand.
+type UnusedAccessReport struct {
+	AccountID  string                  `json:"account_id" yaml:"account_id"`
+	UnusedDays int                     `json:"unused_days" yaml:"unused_days"`
+	Principals []UnusedAccessPrincipal `json:"principals" yaml:"principals"`
+	Errors     []string                `json:"errors" yaml:"errors"`
+}
+
+// FindUnusedAccess analyzes the access of every IAM role and user in the account to help right-size them. Roles are
+// checked with their RoleLastUsed and users with their password and access key last use to find principals unused for
+// unusedDays days. The service last accessed details of each principal, generated by IAM as an asynchronous job, are
+// used to find the services its policies grant that it never accessed or did not access in that time. The jobs of
+// lastAccessedWorkers principals are generated and polled concurrently.
+func FindUnusedAccess(ctx context.Context, cfg aws.Config, unusedDays int) (*UnusedAccessReport, error) {
+	client := iam.NewFromConfig(cfg)
+	report := UnusedAccessReport{
+		UnusedDays: unusedDays,
+		Principals: []UnusedAccessPrincipal{},
+		Errors:     []string{},
+	}
+
+	accountID, err := sts.GetAccountID(ctx, cfg)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return &report, nil
+	}
+	report.AccountID = aws.ToString(accountID)
+
+	principals := []UnusedAccessPrincipal{}
+	roles, err := GetAllRoles(ctx, client)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	for _, role := range roles {
+		principals = append(principals, UnusedAccessPrincipal{
+			Arn:        aws.ToString(role.Arn),
+			Name:       aws.ToString(role.RoleName),
+			Type:       UnusedPrincipalRole,
+			CreateDate: role.CreateDate,
+		})
+	}
+	users, err := GetAllUsers(ctx, client)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	for _, user := range users {
+		principals = append(principals, UnusedAccessPrincipal{
+			Arn:        aws.ToString(user.Arn),
+			Name:       aws.ToString(user.UserName),
+			Type:       UnusedPrincipalUser,
+			CreateDate: user.CreateDate,
+			LastUsed:   user.PasswordLastUsed,
+		})
+	}
+
+	// Analyze the principals concurrently, keeping the results and errors in the order the principals were listed
+	cutoff := time.Now().AddDate(0, 0, -unusedDays)
+	resultErrors := make([][]string, len(principals))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < lastAccessedWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resultErrors[i] = analyzeUnusedAccess(ctx, cfg, client, &principals[i], cutoff)
+			}
+		}()
+	}
+	for i := range principals {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := range principals {
+		report.Errors = append(report.Errors, resultErrors[i]...)
+	}
+	report.Principals = principals
+
+	return &report, nil
+}
+
+// A utility function that completes the access analysis of a single principal, returning any non-fatal errors that
+// occurred. A principal whose last use cannot be retrieved, or a user with an access key whose last use cannot be, is
+// not flagged as unused.
+func analyzeUnusedAccess(ctx context.Context, cfg aws.Config, client *iam.Client, principal *UnusedAccessPrincipal, cutoff time.Time) []string {
+	errs := []string{}
+	principal.NeverAccessedServices = []ServiceAccess{}
+	principal.StaleServices = []ServiceAccess{}
+
+	lastUsedKnown := true
+	lastUseMissing := false
+	switch principal.Type {
+	case UnusedPrincipalRole:
+		// ListRoles omits RoleLastUsed, which GetRole includes
+		role, err := GetRoleDetails(ctx, cfg, principal.Name)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error getting role %s: %v", principal.Name, err))
+			lastUsedKnown = false
+		} else if role.RoleLastUsed != nil {
+			principal.LastUsed = role.RoleLastUsed.LastUsedDate
+			principal.LastUsedRegion = role.RoleLastUsed.Region
+		}
+	case UnusedPrincipalUser:
+		accessKeys, keyErrors, err := getAccessKeys(ctx, client, aws.String(principal.Name))
+		errs = append(errs, keyErrors...)
+		lastUseMissing = len(keyErrors) > 0
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error getting access keys for user %s: %v", principal.Name, err))
+			lastUsedKnown = false
+		}
+		for _, accessKey := range accessKeys {
+			if accessKey.LastUsedDate != nil && (principal.LastUsed == nil || accessKey.LastUsedDate.After(*principal.LastUsed)) {
+				principal.LastUsed = accessKey.LastUsedDate
+				principal.LastUsedRegion = accessKey.LastUsedRegion
+			}
+		}
+	}
+	if lastUsedKnown {
+		if principal.LastUsed != nil {
+			principal.Unused = principal.LastUsed.Before(cutoff)
+		} else {
+			principal.Unused = principal.CreateDate != nil && principal.CreateDate.Before(cutoff)
+		}
+		// An access key whose last use is missing may have been used since the cutoff, so only a recent use of the
+		// other keys or the password is conclusive
+		principal.Unused = principal.Unused && !lastUseMissing
+	}
+
+	services, err := getServiceLastAccessed(ctx, client, principal.Arn)
+	if err != nil {
+		return append(errs, fmt.Sprintf("Error getting service last accessed details for %s: %v", principal.Arn, err))
+	}
+	principal.GrantedServices = len(services)
+	for _, service := range services {
+		access := ServiceAccess{
+			ServiceName:             aws.ToString(service.ServiceName),
+			ServiceNamespace:        aws.ToString(service.ServiceNamespace),
+			LastAuthenticated:       service.LastAuthenticated,
+			LastAuthenticatedRegion: service.LastAuthenticatedRegion,
+		}
+		switch {
+		case service.LastAuthenticated == nil:
+			principal.NeverAccessedServices = append(principal.NeverAccessedServices, access)
+		case service.LastAuthenticated.Before(cutoff):
+			principal.StaleServices = append(principal.StaleServices, access)
+		}
+	}
+
+	return errs
+}
+
+// A utility function that starts a service last accessed job for a principal and polls until AWS reports it as
+// complete, returning every service that the principal's policies grant access to.
+func getServiceLastAccessed(ctx context.Context, client *iam.Client, arn string) ([]types.ServiceLastAccessed, error) {
+	job, err := client.GenerateServiceLastAccessedDetails(ctx, &iam.GenerateServiceLastAccessedDetailsInput{Arn: aws.String(arn)})
+	if err != nil {
+		return nil, err
+	}
+
+	for poll := 0; poll < lastAccessedMaxPolls; poll++ {
+		output, err := client.GetServiceLastAccessedDetails(ctx, &iam.GetServiceLastAccessedDetailsInput{JobId: job.JobId})
+		if err != nil {
+			return nil, err
+		}
+
+		switch output.JobStatus {
+		case types.JobStatusTypeCompleted:
+			services := output.ServicesLastAccessed
+			for output.IsTruncated {
+				output, err = client.GetServiceLastAccessedDetails(ctx, &iam.GetServiceLastAccessedDetailsInput{JobId: job.JobId, Marker: output.Marker})
+				if err != nil {
+					return nil, err
+				}
+				services = append(services, output.ServicesLastAccessed...)
+			}
+			return services, nil
+		case types.JobStatusTypeFailed:
+			if output.Error != nil {
+				return nil, fmt.Errorf("job failed: %s", aws.ToString(output.Error.Message))
+			}
+			return nil, errors.New("job failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lastAccessedPollInterval):
+		}
+	}
+	return nil, errors.New("timed out waiting for the service last accessed details to be generated")
+}
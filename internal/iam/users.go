@@ -0,0 +1,175 @@
+package iam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// GetAllUsers retrieves all Users that are available to the caller.
+func GetAllUsers(ctx context.Context, client *iam.Client) ([]types.User, error) {
+	users := []types.User{}
+
+	output, err := client.ListUsers(ctx, &iam.ListUsersInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	users = append(users, output.Users...)
+
+	for output.IsTruncated {
+		output, err = client.ListUsers(ctx, &iam.ListUsersInput{Marker: output.Marker})
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, output.Users...)
+	}
+
+	return users, nil
+}
+
+// EnrichUser retrieves the policies, group memberships and credentials for a given IAM user. It returns a UserResource
+// struct, a slice of PolicyResource structs that contain the attached policies and permission boundary for the user,
+// and any non-fatal errors that occurred. A piece of the user that cannot be retrieved is left empty.
+func EnrichUser(ctx context.Context, cfg aws.Config, user *types.User) (UserResource, []PolicyResource, []string) {
+	client := iam.NewFromConfig(cfg)
+	userName := aws.ToString(user.UserName)
+	errs := []string{}
+
+	userResource := UserResource{
+		User:                       *user,
+		AttachedPoliciesArns:       []string{},
+		InlinePolicies:             []*InlinePolicy{},
+		Groups:                     []string{},
+		AccessKeys:                 []AccessKeyResource{},
+		MFADevices:                 []types.MFADevice{},
+		SSHPublicKeys:              []types.SSHPublicKeyMetadata{},
+		ServiceSpecificCredentials: []types.ServiceSpecificCredentialMetadata{},
+	}
+
+	// ListUsers omits the permission boundary and tags, which GetUser includes
+	userOutput, err := client.GetUser(ctx, &iam.GetUserInput{UserName: user.UserName})
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("Error getting user %s: %v", userName, err))
+	} else if userOutput.User != nil {
+		userResource.User = *userOutput.User
+	}
+
+	policyReport := GetAttachedPoliciesForUser(ctx, cfg, userName)
+	errs = append(errs, policyReport.Errors...)
+	policies := policyReport.Policies
+	for _, policy := range policyReport.Policies {
+		userResource.AttachedPoliciesArns = append(userResource.AttachedPoliciesArns, *policy.Policy.Arn)
+	}
+
+	if boundary := userResource.User.PermissionsBoundary; boundary != nil && boundary.PermissionsBoundaryArn != nil {
+		boundaryPolicies, boundaryErrors := getPolicyResources(ctx, client, []string{*boundary.PermissionsBoundaryArn})
+		policies = append(policies, boundaryPolicies...)
+		errs = append(errs, boundaryErrors...)
+	}
+
+	inlinePolicies, err := GetInlinePoliciesForUser(ctx, cfg, userName)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("Error getting inline policies for user %s: %v", userName, err))
+	}
+	for _, inlinePolicy := range inlinePolicies {
+		decoded, err := decodeInlinePolicy(inlinePolicy.PolicyName, inlinePolicy.PolicyDocument)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error decoding inline policy %s/%s: %v", aws.ToString(user.Arn), aws.ToString(inlinePolicy.PolicyName), err))
+			continue
+		}
+		userResource.InlinePolicies = append(userResource.InlinePolicies, decoded)
+	}
+
+	groupsPaginator := iam.NewListGroupsForUserPaginator(client, &iam.ListGroupsForUserInput{UserName: user.UserName})
+	for groupsPaginator.HasMorePages() {
+		page, err := groupsPaginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error listing groups for user %s: %v", userName, err))
+			break
+		}
+		for _, group := range page.Groups {
+			userResource.Groups = append(userResource.Groups, aws.ToString(group.GroupName))
+		}
+	}
+
+	accessKeys, keyErrors, err := getAccessKeys(ctx, client, user.UserName)
+	errs = append(errs, keyErrors...)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("Error getting access keys for user %s: %v", userName, err))
+	}
+	userResource.AccessKeys = append(userResource.AccessKeys, accessKeys...)
+
+	mfaPaginator := iam.NewListMFADevicesPaginator(client, &iam.ListMFADevicesInput{UserName: user.UserName})
+	for mfaPaginator.HasMorePages() {
+		page, err := mfaPaginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error listing MFA devices for user %s: %v", userName, err))
+			break
+		}
+		userResource.MFADevices = append(userResource.MFADevices, page.MFADevices...)
+	}
+
+	loginProfileOutput, err := client.GetLoginProfile(ctx, &iam.GetLoginProfileInput{UserName: user.UserName})
+	var noSuchEntity *types.NoSuchEntityException
+	if err != nil && !errors.As(err, &noSuchEntity) {
+		errs = append(errs, fmt.Sprintf("Error getting login profile for user %s: %v", userName, err))
+	} else if err == nil {
+		userResource.LoginProfile = loginProfileOutput.LoginProfile
+	}
+
+	sshPaginator := iam.NewListSSHPublicKeysPaginator(client, &iam.ListSSHPublicKeysInput{UserName: user.UserName})
+	for sshPaginator.HasMorePages() {
+		page, err := sshPaginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error listing SSH public keys for user %s: %v", userName, err))
+			break
+		}
+		userResource.SSHPublicKeys = append(userResource.SSHPublicKeys, page.SSHPublicKeys...)
+	}
+
+	credentialsOutput, err := client.ListServiceSpecificCredentials(ctx, &iam.ListServiceSpecificCredentialsInput{UserName: user.UserName})
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("Error listing service-specific credentials for user %s: %v", userName, err))
+	} else {
+		userResource.ServiceSpecificCredentials = append(userResource.ServiceSpecificCredentials, credentialsOutput.ServiceSpecificCredentials...)
+	}
+
+	return userResource, policies, errs
+}
+
+// A utility function that lists a user's access keys alongside when, where and for which service each was last used. A
+// key whose last use cannot be retrieved is listed without it, and the error is returned as a non-fatal error.
+func getAccessKeys(ctx context.Context, client *iam.Client, userName *string) ([]AccessKeyResource, []string, error) {
+	accessKeys := []AccessKeyResource{}
+	errs := []string{}
+
+	paginator := iam.NewListAccessKeysPaginator(client, &iam.ListAccessKeysInput{UserName: userName})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return accessKeys, errs, err
+		}
+		for _, accessKey := range page.AccessKeyMetadata {
+			accessKeyResource := AccessKeyResource{AccessKey: accessKey}
+			lastUsedOutput, err := client.GetAccessKeyLastUsed(ctx, &iam.GetAccessKeyLastUsedInput{AccessKeyId: accessKey.AccessKeyId})
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("Error getting last use of access key %s for user %s: %v", aws.ToString(accessKey.AccessKeyId), aws.ToString(userName), err))
+				accessKeys = append(accessKeys, accessKeyResource)
+				continue
+			}
+			if lastUsed := lastUsedOutput.AccessKeyLastUsed; lastUsed != nil && lastUsed.LastUsedDate != nil {
+				accessKeyResource.LastUsedDate = lastUsed.LastUsedDate
+				accessKeyResource.LastUsedService = lastUsed.ServiceName
+				accessKeyResource.LastUsedRegion = lastUsed.Region
+			}
+			accessKeys = append(accessKeys, accessKeyResource)
+		}
+	}
+
+	return accessKeys, errs, nil
+}
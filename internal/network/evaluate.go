@@ -0,0 +1,501 @@
+package network
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	methodaws "github.com/Method-Security/methodaws/generated/go"
+	"github.com/Method-Security/methodaws/internal/ec2"
+	"github.com/Method-Security/methodaws/internal/vpc"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+const (
+	anyIPv4 = "0.0.0.0/0"
+	anyIPv6 = "::/0"
+
+	minPort int32 = 0
+	maxPort int32 = 65535
+)
+
+// protocols are the transport protocols that reachability is evaluated for.
+var protocols = []string{"tcp", "udp"}
+
+// listenerPort is a port that a resource is known to listen on.
+type listenerPort struct {
+	protocol string
+	port     int32
+}
+
+// target is the protocol-agnostic view of a resource that reachability is evaluated for. When allPorts is true the
+// resource may listen on any port, otherwise only the listed ports are evaluated.
+type target struct {
+	resourceType      string
+	resourceID        string
+	address           string
+	vpcID             string
+	subnetIDs         []string
+	securityGroupIDs  []string
+	hasSecurityGroups bool
+	allPorts          bool
+	ports             []listenerPort
+	sources           []string
+}
+
+// subnetContext pairs a subnet with the topology of the VPC it belongs to.
+type subnetContext struct {
+	subnet   vpc.Subnet
+	topology vpc.Topology
+}
+
+// AnalyzeReachability computes, entirely offline, which EC2 instances, RDS instances and load balancers in the
+// inventory can be reached from 0.0.0.0/0 or ::/0. A resource is reachable on a port when its subnet routes the
+// source to an internet gateway, the subnet's network ACL allows the traffic inbound, and one of the resource's
+// security groups allows the traffic from the source. Only rules that cover the entire internet are considered. Route
+// tables, network ACLs and security groups that could not be enumerated are assumed to allow the traffic, and the
+// exposures that depend on them are marked unknown rather than left out.
+func AnalyzeReachability(inventory Inventory) []ExposedResource {
+	subnets := map[string]subnetContext{}
+	for _, instance := range inventory.VPCs {
+		for _, subnet := range instance.Topology.Subnets {
+			subnets[aws.ToString(subnet.Subnet.SubnetId)] = subnetContext{subnet: subnet, topology: instance.Topology}
+		}
+	}
+
+	securityGroups := map[string]ec2types.SecurityGroup{}
+	for _, sg := range inventory.SecurityGroups {
+		securityGroups[aws.ToString(sg.GroupId)] = sg
+	}
+
+	exposed := []ExposedResource{}
+	for _, t := range targetsFromInventory(inventory) {
+		if resource, ok := evaluateTarget(t, subnets, securityGroups, inventory.SecurityGroupsUnknown); ok {
+			resource.Region = inventory.Region
+			exposed = append(exposed, resource)
+		}
+	}
+	return exposed
+}
+
+// targetsFromInventory converts every resource that has a public address into a target.
+func targetsFromInventory(inventory Inventory) []target {
+	targets := []target{}
+
+	for _, instance := range inventory.Instances {
+		inst := instance.Instance
+		if inst.State == nil || inst.State.Name != ec2types.InstanceStateNameRunning || inst.SubnetId == nil {
+			continue
+		}
+		t := target{
+			resourceType:      "ec2_instance",
+			resourceID:        aws.ToString(inst.InstanceId),
+			vpcID:             aws.ToString(inst.VpcId),
+			subnetIDs:         []string{aws.ToString(inst.SubnetId)},
+			hasSecurityGroups: true,
+			allPorts:          true,
+		}
+		for _, group := range inst.SecurityGroups {
+			t.securityGroupIDs = append(t.securityGroupIDs, aws.ToString(group.GroupId))
+		}
+		if inst.PublicIpAddress != nil {
+			t.address = *inst.PublicIpAddress
+			t.sources = append(t.sources, anyIPv4)
+		}
+		if inst.Ipv6Address != nil {
+			if t.address == "" {
+				t.address = *inst.Ipv6Address
+			}
+			t.sources = append(t.sources, anyIPv6)
+		}
+		if len(t.sources) > 0 {
+			targets = append(targets, t)
+		}
+	}
+
+	for _, db := range inventory.DBInstances {
+		if !aws.ToBool(db.PubliclyAccessible) || db.Endpoint == nil || db.DBSubnetGroup == nil {
+			continue
+		}
+		t := target{
+			resourceType:      "rds_instance",
+			resourceID:        aws.ToString(db.DBInstanceIdentifier),
+			address:           aws.ToString(db.Endpoint.Address),
+			vpcID:             aws.ToString(db.DBSubnetGroup.VpcId),
+			hasSecurityGroups: true,
+			ports:             []listenerPort{{protocol: "tcp", port: aws.ToInt32(db.Endpoint.Port)}},
+			sources:           []string{anyIPv4},
+		}
+		for _, subnet := range db.DBSubnetGroup.Subnets {
+			t.subnetIDs = append(t.subnetIDs, aws.ToString(subnet.SubnetIdentifier))
+		}
+		for _, group := range db.VpcSecurityGroups {
+			t.securityGroupIDs = append(t.securityGroupIDs, aws.ToString(group.VpcSecurityGroupId))
+		}
+		targets = append(targets, t)
+	}
+
+	for _, lb := range inventory.LoadBalancersV2 {
+		if isInternalLoadBalancer(lb.DnsName) {
+			continue
+		}
+		t := target{
+			resourceType:      "load_balancer",
+			resourceID:        lb.Arn,
+			address:           lb.DnsName,
+			vpcID:             aws.ToString(lb.VpcId),
+			subnetIDs:         lb.SubnetIds,
+			securityGroupIDs:  lb.SecurityGroupIds,
+			hasSecurityGroups: len(lb.SecurityGroupIds) > 0,
+			ports:             listenerPorts(lb.Listeners),
+		}
+		switch lb.IpAddressType {
+		case methodaws.IpAddressTypeDualstack:
+			t.sources = []string{anyIPv4, anyIPv6}
+		case methodaws.IpAddressTypeDualstackWithoutPublicIpv4:
+			t.sources = []string{anyIPv6}
+		default:
+			t.sources = []string{anyIPv4}
+		}
+		targets = append(targets, t)
+	}
+
+	for _, lb := range inventory.LoadBalancersV1 {
+		if isInternalLoadBalancer(lb.DnsName) || lb.VpcId == nil {
+			continue
+		}
+		targets = append(targets, target{
+			resourceType:      "load_balancer",
+			resourceID:        lb.Name,
+			address:           lb.DnsName,
+			vpcID:             aws.ToString(lb.VpcId),
+			subnetIDs:         lb.SubnetIds,
+			securityGroupIDs:  lb.SecurityGroupIds,
+			hasSecurityGroups: len(lb.SecurityGroupIds) > 0,
+			ports:             listenerPorts(lb.Listeners),
+			sources:           []string{anyIPv4},
+		})
+	}
+
+	return targets
+}
+
+// isInternalLoadBalancer reports whether a load balancer is internal. AWS prefixes the DNS name of every internal
+// load balancer with "internal-".
+func isInternalLoadBalancer(dnsName string) bool {
+	return strings.HasPrefix(dnsName, "internal-")
+}
+
+// listenerPorts converts load balancer listeners into the ports they accept traffic on.
+func listenerPorts(listeners []*methodaws.Listener) []listenerPort {
+	ports := []listenerPort{}
+	for _, listener := range listeners {
+		if listener == nil || listener.Protocol == nil {
+			continue
+		}
+		port := int32(listener.Port)
+		switch *listener.Protocol {
+		case methodaws.ProtocolHttp, methodaws.ProtocolHttps, methodaws.ProtocolTcp, methodaws.ProtocolTls:
+			ports = append(ports, listenerPort{protocol: "tcp", port: port})
+		case methodaws.ProtocolUdp:
+			ports = append(ports, listenerPort{protocol: "udp", port: port})
+		case methodaws.ProtocolTcpUdp:
+			ports = append(ports, listenerPort{protocol: "tcp", port: port}, listenerPort{protocol: "udp", port: port})
+		}
+	}
+	return ports
+}
+
+// evaluateTarget returns the exposures of a target through the first of its subnets that exposes it. The target's
+// security groups are unknown when one of them is missing from an incomplete security group enumeration.
+func evaluateTarget(t target, subnets map[string]subnetContext, securityGroups map[string]ec2types.SecurityGroup, securityGroupsUnknown bool) (ExposedResource, bool) {
+	groups := []ec2types.SecurityGroup{}
+	groupsUnknown := false
+	for _, groupID := range t.securityGroupIDs {
+		if sg, ok := securityGroups[groupID]; ok {
+			groups = append(groups, sg)
+		} else if securityGroupsUnknown {
+			groupsUnknown = true
+		}
+	}
+
+	for _, subnetID := range t.subnetIDs {
+		sc, ok := subnets[subnetID]
+		if !ok {
+			continue
+		}
+		routeTable, _ := findRouteTable(sc.topology, sc.subnet.RouteTableID)
+		networkACL, hasNetworkACL := findNetworkACL(sc.topology, sc.subnet.NetworkACLID)
+
+		exposures := []Exposure{}
+		for _, source := range t.sources {
+			prefix, routeUnknown, ok := routePath(sc, routeTable, t.vpcID, subnetID, source)
+			if !ok {
+				continue
+			}
+
+			for _, protocol := range protocols {
+				for _, r := range candidateRanges(t, protocol) {
+					exposures = append(exposures, evaluateRange(networkACL, hasNetworkACL, groups, t.hasSecurityGroups, groupsUnknown, source, protocol, r[0], r[1], prefix, routeUnknown)...)
+				}
+			}
+		}
+
+		if len(exposures) > 0 {
+			return ExposedResource{
+				ResourceType: t.resourceType,
+				ResourceID:   t.resourceID,
+				Address:      t.address,
+				VPCID:        t.vpcID,
+				SubnetID:     subnetID,
+				Exposures:    exposures,
+			}, true
+		}
+	}
+
+	return ExposedResource{}, false
+}
+
+// routePath returns the internet gateway and route table steps that route the source into a subnet. When the route
+// tables of the subnet's region could not be described, the source is assumed to be routed and the path is unknown.
+func routePath(sc subnetContext, routeTable ec2types.RouteTable, vpcID string, subnetID string, source string) ([]PathStep, bool, bool) {
+	if sc.topology.RouteTablesUnknown {
+		return []PathStep{{
+			Component: "route_table",
+			Detail:    fmt.Sprintf("route tables were not enumerated, assuming %s is routed to an internet gateway for %s", source, subnetID),
+		}}, true, true
+	}
+
+	route, ok := internetRoute(routeTable, source)
+	if !ok {
+		return nil, false, false
+	}
+	return []PathStep{
+		{
+			Component: "internet_gateway",
+			ID:        aws.ToString(route.GatewayId),
+			Detail:    fmt.Sprintf("attached to %s", vpcID),
+		},
+		{
+			Component: "route_table",
+			ID:        sc.subnet.RouteTableID,
+			Detail:    fmt.Sprintf("routes %s to %s for %s", source, aws.ToString(route.GatewayId), subnetID),
+		},
+	}, false, true
+}
+
+// candidateRanges returns the port ranges of a protocol that should be evaluated for a target.
+func candidateRanges(t target, protocol string) [][2]int32 {
+	if t.allPorts {
+		return [][2]int32{{minPort, maxPort}}
+	}
+	ranges := [][2]int32{}
+	for _, p := range t.ports {
+		if p.protocol == protocol {
+			ranges = append(ranges, [2]int32{p.port, p.port})
+		}
+	}
+	return ranges
+}
+
+// evaluateRange splits a port range into the sub-ranges within which the network ACL and security group decisions
+// are constant, evaluates each sub-range once, and merges adjacent allowed sub-ranges that share the same path. An
+// exposure is unknown when its route or any of its decisions was assumed.
+func evaluateRange(networkACL ec2types.NetworkAcl, hasNetworkACL bool, groups []ec2types.SecurityGroup, hasSecurityGroups bool, groupsUnknown bool, source string, protocol string, from int32, to int32, prefix []PathStep, routeUnknown bool) []Exposure {
+	boundaries := map[int32]bool{from: true, to + 1: true}
+	addBoundaries := func(start *int32, end *int32) {
+		if start != nil && *start > from && *start <= to {
+			boundaries[*start] = true
+		}
+		if end != nil && *end >= from && *end < to {
+			boundaries[*end+1] = true
+		}
+	}
+	if hasNetworkACL {
+		for _, entry := range networkACL.Entries {
+			if entry.PortRange != nil {
+				addBoundaries(entry.PortRange.From, entry.PortRange.To)
+			}
+		}
+	}
+	for _, sg := range groups {
+		for _, rule := range ec2.NormalizeSecurityGroupRules(sg) {
+			addBoundaries(rule.FromPort, rule.ToPort)
+		}
+	}
+
+	points := []int32{}
+	for point := range boundaries {
+		points = append(points, point)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i] < points[j] })
+
+	exposures := []Exposure{}
+	previousKey := ""
+	for i := 0; i < len(points)-1; i++ {
+		start, end := points[i], points[i+1]-1
+
+		aclStep, allowed, aclUnknown := networkACLDecision(networkACL, hasNetworkACL, source, protocol, start)
+		if !allowed {
+			previousKey = ""
+			continue
+		}
+		sgStep, allowed, sgUnknown := securityGroupDecision(groups, hasSecurityGroups, groupsUnknown, source, protocol, start)
+		if !allowed {
+			previousKey = ""
+			continue
+		}
+
+		key := aclStep.ID + aclStep.Detail + sgStep.ID + sgStep.Detail
+		if key == previousKey && len(exposures) > 0 {
+			exposures[len(exposures)-1].ToPort = end
+			continue
+		}
+		previousKey = key
+
+		path := append(append([]PathStep{}, prefix...), aclStep, sgStep)
+		exposures = append(exposures, Exposure{
+			Protocol: protocol,
+			FromPort: start,
+			ToPort:   end,
+			Source:   source,
+			Path:     path,
+			Unknown:  routeUnknown || aclUnknown || sgUnknown,
+		})
+	}
+	return exposures
+}
+
+// networkACLDecision evaluates the inbound entries of a network ACL in rule number order and returns the step for the
+// first entry that matches the source, protocol and port, and whether the traffic is allowed. Traffic that matches no
+// entry is denied. A network ACL that was not enumerated is assumed to allow the traffic, which is reported as unknown.
+func networkACLDecision(networkACL ec2types.NetworkAcl, hasNetworkACL bool, source string, protocol string, port int32) (PathStep, bool, bool) {
+	if !hasNetworkACL {
+		return PathStep{Component: "network_acl", Detail: "network ACL was not enumerated, assuming traffic is allowed"}, true, true
+	}
+
+	entries := append([]ec2types.NetworkAclEntry{}, networkACL.Entries...)
+	sort.Slice(entries, func(i, j int) bool { return aws.ToInt32(entries[i].RuleNumber) < aws.ToInt32(entries[j].RuleNumber) })
+
+	for _, entry := range entries {
+		if aws.ToBool(entry.Egress) {
+			continue
+		}
+		if aws.ToString(entry.CidrBlock) != source && aws.ToString(entry.Ipv6CidrBlock) != source {
+			continue
+		}
+		entryProtocol := aws.ToString(entry.Protocol)
+		if entryProtocol != "-1" && entryProtocol != protocolNumber(protocol) {
+			continue
+		}
+		if entry.PortRange != nil && (port < aws.ToInt32(entry.PortRange.From) || port > aws.ToInt32(entry.PortRange.To)) {
+			continue
+		}
+
+		if entry.RuleAction != ec2types.RuleActionAllow {
+			return PathStep{}, false, false
+		}
+		return PathStep{
+			Component: "network_acl",
+			ID:        aws.ToString(networkACL.NetworkAclId),
+			Detail:    fmt.Sprintf("inbound rule %d allows %s from %s", aws.ToInt32(entry.RuleNumber), describeNetworkACLTraffic(entry), source),
+		}, true, false
+	}
+	return PathStep{}, false, false
+}
+
+// securityGroupDecision returns the step for the first security group rule that allows the source, protocol and
+// port, and whether the traffic is allowed. Resources without security groups, such as some network load balancers,
+// do not filter traffic. When no known rule allows the traffic but some of the groups were not enumerated, the
+// traffic is assumed to be allowed and reported as unknown.
+func securityGroupDecision(groups []ec2types.SecurityGroup, hasSecurityGroups bool, groupsUnknown bool, source string, protocol string, port int32) (PathStep, bool, bool) {
+	if !hasSecurityGroups {
+		return PathStep{Component: "security_group", Detail: "resource has no security groups"}, true, false
+	}
+
+	for _, sg := range groups {
+		for _, rule := range ec2.NormalizeSecurityGroupRules(sg) {
+			if rule.Direction != "ingress" {
+				continue
+			}
+			if aws.ToString(rule.CIDR) != source && aws.ToString(rule.IPv6CIDR) != source {
+				continue
+			}
+			if !ec2.RuleAllowsPort(rule, protocol, port) {
+				continue
+			}
+			return PathStep{
+				Component: "security_group",
+				ID:        aws.ToString(sg.GroupId),
+				Detail:    fmt.Sprintf("allows %s from %s", describeSecurityGroupTraffic(rule), source),
+			}, true, false
+		}
+	}
+	if groupsUnknown {
+		return PathStep{Component: "security_group", Detail: "security groups were not enumerated, assuming traffic is allowed"}, true, true
+	}
+	return PathStep{}, false, false
+}
+
+// internetRoute returns the route that sends the source to an internet gateway, if any.
+func internetRoute(routeTable ec2types.RouteTable, source string) (ec2types.Route, bool) {
+	for _, route := range routeTable.Routes {
+		if !strings.HasPrefix(aws.ToString(route.GatewayId), "igw-") || route.State == ec2types.RouteStateBlackhole {
+			continue
+		}
+		if aws.ToString(route.DestinationCidrBlock) == source || aws.ToString(route.DestinationIpv6CidrBlock) == source {
+			return route, true
+		}
+	}
+	return ec2types.Route{}, false
+}
+
+func findRouteTable(topology vpc.Topology, routeTableID string) (ec2types.RouteTable, bool) {
+	for _, routeTable := range topology.RouteTables {
+		if aws.ToString(routeTable.RouteTableId) == routeTableID {
+			return routeTable, true
+		}
+	}
+	return ec2types.RouteTable{}, false
+}
+
+func findNetworkACL(topology vpc.Topology, networkACLID string) (ec2types.NetworkAcl, bool) {
+	for _, networkACL := range topology.NetworkACLs {
+		if aws.ToString(networkACL.NetworkAclId) == networkACLID {
+			return networkACL, true
+		}
+	}
+	return ec2types.NetworkAcl{}, false
+}
+
+// protocolNumber returns the IANA protocol number that network ACL entries use in place of the protocol name.
+func protocolNumber(protocol string) string {
+	switch protocol {
+	case "tcp":
+		return "6"
+	case "udp":
+		return "17"
+	}
+	return protocol
+}
+
+func describeNetworkACLTraffic(entry ec2types.NetworkAclEntry) string {
+	if aws.ToString(entry.Protocol) == "-1" {
+		return "all traffic"
+	}
+	if entry.PortRange == nil {
+		return fmt.Sprintf("protocol %s on all ports", aws.ToString(entry.Protocol))
+	}
+	return fmt.Sprintf("protocol %s ports %d-%d", aws.ToString(entry.Protocol), aws.ToInt32(entry.PortRange.From), aws.ToInt32(entry.PortRange.To))
+}
+
+func describeSecurityGroupTraffic(rule ec2.SecurityGroupRule) string {
+	if rule.Protocol == "-1" {
+		return "all traffic"
+	}
+	if rule.FromPort == nil || rule.ToPort == nil {
+		return fmt.Sprintf("%s on all ports", rule.Protocol)
+	}
+	return fmt.Sprintf("%s ports %d-%d", rule.Protocol, *rule.FromPort, *rule.ToPort)
+}
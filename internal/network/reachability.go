@@ -0,0 +1,85 @@
+package network
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Method-Security/methodaws/internal/ec2"
+	"github.com/Method-Security/methodaws/internal/loadbalancer"
+	"github.com/Method-Security/methodaws/internal/rds"
+	"github.com/Method-Security/methodaws/internal/sts"
+	"github.com/Method-Security/methodaws/internal/vpc"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// GatherInventoryForRegion enumerates the VPC topology, security groups, EC2 instances, RDS instances and load
+// balancers of a region using the existing enumeration functions. Failures to enumerate individual resource types
+// are returned as non-fatal errors so that reachability can still be computed from whatever was collected.
+func GatherInventoryForRegion(ctx context.Context, cfg aws.Config, region string) (Inventory, []string) {
+	inventory := Inventory{Region: region}
+	errors := []string{}
+
+	vpcReport, err := vpc.EnumerateVPCForRegion(ctx, cfg, region)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	inventory.VPCs = vpcReport.VPCs
+	errors = append(errors, vpcReport.Errors...)
+
+	securityGroups, sgErrors := ec2.EnumerateSecurityGroupForRegion(ctx, cfg, nil, region)
+	inventory.SecurityGroups = securityGroups
+	inventory.SecurityGroupsUnknown = len(sgErrors) > 0
+	errors = append(errors, sgErrors...)
+
+	ec2Report, err := ec2.EnumerateEc2ForRegion(ctx, cfg, region)
+	if err != nil {
+		errors = append(errors, err.Error())
+	} else {
+		inventory.Instances = ec2Report.Resources.EC2Instances
+		errors = append(errors, ec2Report.Errors...)
+	}
+
+	rdsReport, err := rds.EnumerateRdsForRegion(ctx, cfg, region)
+	if err != nil {
+		errors = append(errors, err.Error())
+	} else {
+		inventory.DBInstances = rdsReport.Resources.RDSInstances
+		errors = append(errors, rdsReport.Errors...)
+	}
+
+	v2Report := loadbalancer.EnumerateV2LBsForRegion(ctx, cfg, region)
+	inventory.LoadBalancersV2 = v2Report.V2LoadBalancers
+	errors = append(errors, v2Report.Errors...)
+
+	v1Report := loadbalancer.EnumerateV1ELBsForRegion(ctx, cfg, region)
+	inventory.LoadBalancersV1 = v1Report.V1LoadBalancers
+	errors = append(errors, v1Report.Errors...)
+
+	return inventory, errors
+}
+
+// EnumerateReachability computes which resources are reachable from the internet in each of the specified regions and
+// returns a ReachabilityReport. Each exposed resource includes the path that explains why it is reachable. Non-fatal
+// errors that occur while gathering the inventory are included in the report.
+func EnumerateReachability(ctx context.Context, cfg aws.Config, regions []string) (*ReachabilityReport, error) {
+	accountID, err := sts.GetAccountID(ctx, cfg)
+	if err != nil {
+		return &ReachabilityReport{ExposedResources: []ExposedResource{}, Errors: []string{err.Error()}}, err
+	}
+
+	report := ReachabilityReport{
+		AccountID:        aws.ToString(accountID),
+		ExposedResources: []ExposedResource{},
+		Errors:           []string{},
+	}
+
+	for _, region := range regions {
+		inventory, errors := GatherInventoryForRegion(ctx, cfg, region)
+		for _, e := range errors {
+			report.Errors = append(report.Errors, fmt.Sprintf("Error in region %s: %s", region, e))
+		}
+		report.ExposedResources = append(report.ExposedResources, AnalyzeReachability(inventory)...)
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,63 @@
+// Package network provides the data structures and logic necessary to analyze how AWS resources can be reached over
+// the network, combining VPC topology, network ACLs and security groups.
+package network
+
+import (
+	methodaws "github.com/Method-Security/methodaws/generated/go"
+	"github.com/Method-Security/methodaws/internal/ec2"
+	"github.com/Method-Security/methodaws/internal/vpc"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// PathStep is a single hop on the path from the internet to a resource, naming the AWS component that lets the
+// traffic through and why.
+type PathStep struct {
+	Component string `json:"component" yaml:"component"`
+	ID        string `json:"id" yaml:"id"`
+	Detail    string `json:"detail" yaml:"detail"`
+}
+
+// Exposure is a contiguous range of ports on a single protocol that can be reached from Source, alongside the path
+// that explains why the traffic is allowed. Unknown is set when a step of the path was assumed to allow the traffic
+// because the route tables, network ACL or security groups it depends on could not be enumerated.
+type Exposure struct {
+	Protocol string     `json:"protocol" yaml:"protocol"`
+	FromPort int32      `json:"from_port" yaml:"from_port"`
+	ToPort   int32      `json:"to_port" yaml:"to_port"`
+	Source   string     `json:"source" yaml:"source"`
+	Path     []PathStep `json:"path" yaml:"path"`
+	Unknown  bool       `json:"unknown" yaml:"unknown"`
+}
+
+// ExposedResource is an EC2 instance, RDS instance or load balancer that can be reached from the internet.
+type ExposedResource struct {
+	ResourceType string     `json:"resource_type" yaml:"resource_type"`
+	ResourceID   string     `json:"resource_id" yaml:"resource_id"`
+	Address      string     `json:"address" yaml:"address"`
+	Region       string     `json:"region" yaml:"region"`
+	VPCID        string     `json:"vpc_id" yaml:"vpc_id"`
+	SubnetID     string     `json:"subnet_id" yaml:"subnet_id"`
+	Exposures    []Exposure `json:"exposures" yaml:"exposures"`
+}
+
+// Inventory contains the previously enumerated resources of a single region that reachability is computed from.
+// SecurityGroupsUnknown is set when the security groups of the region could not be enumerated in full.
+type Inventory struct {
+	Region                string
+	VPCs                  []vpc.Instance
+	SecurityGroups        []ec2types.SecurityGroup
+	SecurityGroupsUnknown bool
+	Instances             []ec2.InstanceWithIAMRole
+	DBInstances           []rdstypes.DBInstance
+	LoadBalancersV2       []*methodaws.LoadBalancerV2
+	LoadBalancersV1       []*methodaws.LoadBalancerV1
+}
+
+// ReachabilityReport contains the account ID, the resources that are reachable from the internet, and any non-fatal
+// errors that occurred during the execution of the `methodaws network reachability` subcommand.
+type ReachabilityReport struct {
+	AccountID        string            `json:"account_id" yaml:"account_id"`
+	ExposedResources []ExposedResource `json:"exposed_resources" yaml:"exposed_resources"`
+	Errors           []string          `json:"errors" yaml:"errors"`
+}
@@ -0,0 +1,348 @@
+package s3
+
+import (
+	"strings"
+
+	methodaws "github.com/Method-Security/methodaws/generated/go"
+	"github.com/Method-Security/methodaws/internal/common"
+	"github.com/Method-Security/methodaws/internal/iam/policy"
+)
+
+// readActions and writeActions are representative actions used to decide whether a statement grants read or write
+// access to a bucket's data.
+var (
+	readActions  = []string{"s3:GetObject", "s3:GetObjectVersion", "s3:ListBucket", "s3:ListBucketVersions"}
+	writeActions = []string{"s3:PutObject", "s3:DeleteObject", "s3:DeleteObjectVersion", "s3:PutObjectAcl", "s3:PutBucketAcl", "s3:PutBucketPolicy"}
+)
+
+// restrictingConditionKeys are the condition keys that, when pinned to fixed values, restrict a statement to known
+// networks, organizations, accounts or principals. These follow the keys S3 itself uses to decide whether a bucket
+// policy is public.
+var restrictingConditionKeys = map[string]bool{
+	"aws:sourceip":              true,
+	"aws:sourcevpc":             true,
+	"aws:sourcevpce":            true,
+	"aws:sourcearn":             true,
+	"aws:sourceaccount":         true,
+	"aws:sourceowner":           true,
+	"aws:principalorgid":        true,
+	"aws:principalaccount":      true,
+	"aws:principalarn":          true,
+	"aws:userid":                true,
+	"s3:dataaccesspointarn":     true,
+	"s3:dataaccesspointaccount": true,
+}
+
+const (
+	allUsersURI           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	authenticatedUsersURI = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+// analyzeBucketAccess evaluates a bucket's policy and ACL grants and classifies the bucket as private, public read,
+// public write or cross-account. Only Allow statements grant access. A Deny statement that applies to every principal
+// and covers an Allow statement's actions and resources restricts it when all of its conditions negate a restricting
+// key, e.g. StringNotEquals on aws:SourceVpce; other Deny statements are listed but are not subtracted from the access
+// that Allow statements grant. The bucket's effective Public Access Block is taken into account:
+// RestrictPublicBuckets blocks all cross-account access granted by a public policy and IgnorePublicAcls blocks public
+// ACL grants. ACLs are ignored entirely when object ownership is BucketOwnerEnforced. When the policy could not be
+// fetched or parsed, or the ACL grants could not be fetched, the analysis is marked as incomplete and a bucket that would
+// otherwise be private is classified as unknown.
+func analyzeBucketAccess(bucket *methodaws.Bucket, accountID string, policyKnown bool) (*methodaws.S3AccessAnalysis, error) {
+	analysis := &methodaws.S3AccessAnalysis{
+		Classification:     methodaws.S3AccessClassificationPrivate,
+		ExternalPrincipals: []*methodaws.S3ExternalPrincipal{},
+		Statements:         []*methodaws.S3PolicyStatement{},
+		PolicyUnknown:      !policyKnown,
+	}
+	publicAccessConfig := bucket.EffectivePublicAccessConfig
+	if publicAccessConfig == nil {
+		publicAccessConfig = bucket.PublicAccessConfig
+	}
+	if publicAccessConfig == nil {
+		publicAccessConfig = &methodaws.S3PublicAccessBlockConfiguration{}
+	}
+
+	var policyErr error
+	if bucket.Policy != nil && *bucket.Policy != "" {
+		document, err := policy.Parse(*bucket.Policy)
+		if err != nil {
+			policyErr = err
+			analysis.PolicyUnknown = true
+		} else {
+			policyPrincipals := []*methodaws.S3ExternalPrincipal{}
+			publicPolicy := false
+			denies := []policy.Statement{}
+			for _, statement := range document.Statement {
+				if statement.IsDeny() {
+					denies = append(denies, statement)
+				}
+			}
+			for _, statement := range document.Statement {
+				analysis.Statements = append(analysis.Statements, toS3PolicyStatement(statement))
+				for _, principal := range statementExternalPrincipals(statement, denies, bucket.OwnerId, accountID) {
+					publicPolicy = publicPolicy || principal.Public
+					policyPrincipals = append(policyPrincipals, principal)
+				}
+			}
+			for _, principal := range policyPrincipals {
+				principal.BlockedByPublicAccessBlock = publicPolicy && publicAccessConfig.RestrictPublicBuckets
+			}
+			analysis.ExternalPrincipals = append(analysis.ExternalPrincipals, policyPrincipals...)
+		}
+	}
+
+	aclsDisabled := bucket.ObjectOwnership != nil && *bucket.ObjectOwnership == methodaws.S3ObjectOwnershipBucketOwnerEnforced
+	// The grants are only nil when they could not be fetched
+	analysis.AclUnknown = !aclsDisabled && bucket.AclGrants == nil
+	if !aclsDisabled {
+		for _, grant := range bucket.AclGrants {
+			if principal, ok := grantExternalPrincipal(grant, bucket.OwnerId); ok {
+				principal.BlockedByPublicAccessBlock = principal.Public && publicAccessConfig.IgnorePublicAcls
+				analysis.ExternalPrincipals = append(analysis.ExternalPrincipals, principal)
+			}
+		}
+	}
+
+	for _, principal := range analysis.ExternalPrincipals {
+		if principal.BlockedByPublicAccessBlock {
+			continue
+		}
+		if principal.Public {
+			analysis.PublicRead = analysis.PublicRead || principal.Read
+			analysis.PublicWrite = analysis.PublicWrite || principal.Write
+		} else {
+			analysis.CrossAccount = true
+		}
+	}
+
+	switch {
+	case analysis.PublicWrite:
+		analysis.Classification = methodaws.S3AccessClassificationPublicWrite
+	case analysis.PublicRead:
+		analysis.Classification = methodaws.S3AccessClassificationPublicRead
+	case analysis.CrossAccount:
+		analysis.Classification = methodaws.S3AccessClassificationCrossAccount
+	case analysis.PolicyUnknown || analysis.AclUnknown:
+		analysis.Classification = methodaws.S3AccessClassificationUnknown
+	}
+
+	return analysis, policyErr
+}
+
+// statementExternalPrincipals returns the principals outside of the bucket owner's account that an Allow statement
+// grants access to. Service principals are not considered external. A wildcard principal is public unless the
+// statement pins it with a restricting condition, or one of the Deny statements restricts it with a negated one, in
+// which case it is reported as restricted cross-account access.
+func statementExternalPrincipals(statement policy.Statement, denies []policy.Statement, ownerID string, accountID string) []*methodaws.S3ExternalPrincipal {
+	if !statement.IsAllow() {
+		return nil
+	}
+
+	read, write := false, false
+	for _, action := range readActions {
+		read = read || statement.MatchesAction(action)
+	}
+	for _, action := range writeActions {
+		write = write || statement.MatchesAction(action)
+	}
+	restrictingConditions := append(statementRestrictingConditions(statement), denyRestrictingConditions(statement, denies)...)
+
+	newPrincipal := func(principal string, principalAccountID string, wildcard bool) *methodaws.S3ExternalPrincipal {
+		externalPrincipal := &methodaws.S3ExternalPrincipal{
+			Principal:             principal,
+			Source:                methodaws.S3AccessSourcePolicy,
+			Public:                wildcard && len(restrictingConditions) == 0,
+			Read:                  read,
+			Write:                 write,
+			RestrictingConditions: restrictingConditions,
+		}
+		if principalAccountID != "" {
+			externalPrincipal.AccountId = &principalAccountID
+		}
+		return externalPrincipal
+	}
+
+	// A wildcard pinned to the bucket owner's own account does not grant external access
+	if restrictedToAccount(restrictingConditions, accountID) {
+		return nil
+	}
+
+	// NotPrincipal with Allow grants access to everyone except the listed principals
+	if statement.NotPrincipal != nil {
+		return []*methodaws.S3ExternalPrincipal{newPrincipal("*", "", true)}
+	}
+
+	principals := []*methodaws.S3ExternalPrincipal{}
+	for _, principal := range statement.Principal.Principals() {
+		principalType, value, found := strings.Cut(principal, ":")
+		if !found {
+			principals = append(principals, newPrincipal(principal, "", true))
+			continue
+		}
+		switch principalType {
+		case "AWS":
+			if value == "*" {
+				principals = append(principals, newPrincipal(principal, "", true))
+				continue
+			}
+			principalAccountID := policy.PrincipalAccount(value)
+			if principalAccountID == accountID {
+				continue
+			}
+			principals = append(principals, newPrincipal(principal, principalAccountID, false))
+		case "CanonicalUser":
+			if value == ownerID {
+				continue
+			}
+			principals = append(principals, newPrincipal(principal, "", false))
+		case "Federated":
+			principals = append(principals, newPrincipal(principal, "", false))
+		}
+	}
+	return principals
+}
+
+// statementRestrictingConditions returns the conditions of a statement that pin a restricting key to fixed values.
+// Negated operators, IfExists operators and wildcard values do not restrict access.
+func statementRestrictingConditions(statement policy.Statement) []*methodaws.S3PolicyCondition {
+	restricting := []*methodaws.S3PolicyCondition{}
+	for _, condition := range statementConditions(statement) {
+		if strings.Contains(condition.Operator, "Not") || strings.HasSuffix(condition.Operator, "IfExists") {
+			continue
+		}
+		if restrictingCondition(condition) {
+			restricting = append(restricting, condition)
+		}
+	}
+	return restricting
+}
+
+// denyRestrictingConditions returns the conditions of the Deny statements that restrict an Allow statement. A Deny
+// statement restricts it when it applies to every principal, covers every read and write action and every resource the
+// Allow statement grants, and all of its conditions pin a restricting key with a negated operator, so that requests
+// from outside the pinned networks, organizations, accounts or principals are denied.
+func denyRestrictingConditions(allow policy.Statement, denies []policy.Statement) []*methodaws.S3PolicyCondition {
+	restricting := []*methodaws.S3PolicyCondition{}
+	for _, deny := range denies {
+		if deny.NotPrincipal != nil || len(deny.NotResource) > 0 || len(deny.Condition) == 0 {
+			continue
+		}
+		everyone := false
+		for _, principal := range deny.Principal.Principals() {
+			everyone = everyone || principal == "*" || principal == "AWS:*"
+		}
+		if !everyone || !denyCoversActions(allow, deny) || !denyCoversResources(allow, deny) {
+			continue
+		}
+
+		conditions := statementConditions(deny)
+		negated := true
+		for _, condition := range conditions {
+			negated = negated && strings.Contains(condition.Operator, "Not") && restrictingCondition(condition)
+		}
+		if negated {
+			restricting = append(restricting, conditions...)
+		}
+	}
+	return restricting
+}
+
+// denyCoversActions reports whether a Deny statement covers every representative read and write action that an Allow
+// statement grants.
+func denyCoversActions(allow policy.Statement, deny policy.Statement) bool {
+	granted := false
+	for _, action := range append(append([]string{}, readActions...), writeActions...) {
+		if !allow.MatchesAction(action) {
+			continue
+		}
+		granted = true
+		if !deny.MatchesAction(action) {
+			return false
+		}
+	}
+	return granted
+}
+
+// denyCoversResources reports whether a Deny statement's Resource element covers every resource that an Allow
+// statement grants. An Allow statement that uses NotResource is only covered by a Deny statement on every resource.
+func denyCoversResources(allow policy.Statement, deny policy.Statement) bool {
+	resources := []string(allow.Resource)
+	if len(allow.NotResource) > 0 || resources == nil {
+		resources = []string{"*"}
+	}
+	for _, resource := range resources {
+		covered := false
+		for _, pattern := range deny.Resource {
+			covered = covered || common.WildcardMatch(pattern, resource)
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// restrictingCondition reports whether a condition pins a restricting key to fixed values. Wildcard values do not
+// restrict access.
+func restrictingCondition(condition *methodaws.S3PolicyCondition) bool {
+	if !restrictingConditionKeys[strings.ToLower(condition.Key)] || len(condition.Values) == 0 {
+		return false
+	}
+	for _, value := range condition.Values {
+		if value == "*" || value == "0.0.0.0/0" || value == "::/0" {
+			return false
+		}
+	}
+	return true
+}
+
+// restrictedToAccount reports whether the restricting conditions include an account condition that only allows the
+// given account.
+func restrictedToAccount(conditions []*methodaws.S3PolicyCondition, accountID string) bool {
+	for _, condition := range conditions {
+		key := strings.ToLower(condition.Key)
+		if key != "aws:principalaccount" && key != "aws:sourceaccount" {
+			continue
+		}
+		if len(condition.Values) == 1 && condition.Values[0] == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// grantExternalPrincipal returns the external principal that an ACL grant gives access to. Grants to the bucket owner
+// and to the S3 log delivery group are not external.
+func grantExternalPrincipal(grant *methodaws.S3BucketGrant, ownerID string) (*methodaws.S3ExternalPrincipal, bool) {
+	principal := &methodaws.S3ExternalPrincipal{
+		Source: methodaws.S3AccessSourceAcl,
+		Read:   grant.Permission == "READ" || grant.Permission == "FULL_CONTROL",
+		Write:  grant.Permission == "WRITE" || grant.Permission == "WRITE_ACP" || grant.Permission == "FULL_CONTROL",
+	}
+
+	switch grant.GranteeType {
+	case methodaws.S3GranteeTypeGroup:
+		uri := ""
+		if grant.GranteeUri != nil {
+			uri = *grant.GranteeUri
+		}
+		if uri != allUsersURI && uri != authenticatedUsersURI {
+			return nil, false
+		}
+		principal.Principal = uri
+		principal.Public = true
+	case methodaws.S3GranteeTypeCanonicalUser:
+		if grant.GranteeId == nil || *grant.GranteeId == ownerID {
+			return nil, false
+		}
+		principal.Principal = "CanonicalUser:" + *grant.GranteeId
+	case methodaws.S3GranteeTypeAmazonCustomerByEmail:
+		if grant.GranteeEmail == nil {
+			return nil, false
+		}
+		principal.Principal = "Email:" + *grant.GranteeEmail
+	default:
+		return nil, false
+	}
+	return principal, true
+}
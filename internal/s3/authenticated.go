@@ -0,0 +1,67 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+
+	methodaws "github.com/Method-Security/methodaws/generated/go"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// checkAuthenticatedAccess repeats the listing, read, ACL and policy checks with the caller's own credentials. The read
+// check uses the objects listed anonymously, or the caller's own listing if the bucket could not be listed anonymously.
+// The checks that are allowed for the caller but not anonymously are recorded in AuthenticatedOnly. Failures to list the
+// bucket or read its policy status are returned as non-fatal errors, and PolicyIsPublic is left unset when the status
+// cannot be read.
+func checkAuthenticatedAccess(ctx context.Context, cfg aws.Config, bucketName string, region string, externalBucket *methodaws.ExternalBucket) (*methodaws.ExternalBucketAuthenticatedAccess, []string) {
+	cfg.Region = region
+	client := s3.NewFromConfig(cfg)
+	errors := []string{}
+
+	access := &methodaws.ExternalBucketAuthenticatedAccess{
+		AuthenticatedOnly: []methodaws.ExternalBucketAccessCheck{},
+	}
+
+	access.AllowDirectoryListing = checkListingAllowed(ctx, client, bucketName)
+	directoryContents := externalBucket.DirectoryContents
+	if len(directoryContents) == 0 && access.AllowDirectoryListing {
+		var err error
+		directoryContents, err = listBucketContents(ctx, client, bucketName)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Error listing bucket %s with authenticated credentials: %v", bucketName, err))
+		}
+	}
+	access.AllowRead = checkAnonymousReadAllowed(ctx, client, bucketName, directoryContents)
+
+	_, err := checkACL(ctx, client, bucketName)
+	access.AllowAclRead = err == nil
+
+	_, err = checkPolicy(ctx, client, bucketName)
+	access.AllowPolicyRead = err == nil
+
+	policyIsPublic, err := checkPolicyStatus(ctx, client, bucketName)
+	if err == nil {
+		access.PolicyIsPublic = policyIsPublic
+	} else {
+		errors = append(errors, fmt.Sprintf("Error getting bucket policy status of %s with authenticated credentials: %v", bucketName, err))
+	}
+
+	differences := []struct {
+		check         methodaws.ExternalBucketAccessCheck
+		anonymous     bool
+		authenticated bool
+	}{
+		{methodaws.ExternalBucketAccessCheckList, externalBucket.AllowDirectoryListing, access.AllowDirectoryListing},
+		{methodaws.ExternalBucketAccessCheckRead, externalBucket.AllowAnonymousRead, access.AllowRead},
+		{methodaws.ExternalBucketAccessCheckAclRead, externalBucket.AllowAclRead, access.AllowAclRead},
+		{methodaws.ExternalBucketAccessCheckPolicyRead, externalBucket.Policy != nil, access.AllowPolicyRead},
+	}
+	for _, difference := range differences {
+		if difference.authenticated && !difference.anonymous {
+			access.AuthenticatedOnly = append(access.AuthenticatedOnly, difference.check)
+		}
+	}
+
+	return access, errors
+}
@@ -0,0 +1,291 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	methodaws "github.com/Method-Security/methodaws/generated/go"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// bucketConfigurationFunc fetches one piece of bucket configuration and records it on the bucket. The returned error is
+// specific to that configuration and is recorded on the bucket itself rather than on the report.
+type bucketConfigurationFunc func(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bucket) (*methodaws.Bucket, error)
+
+// bucketConfigurations lists the configuration fetched for every bucket in addition to its policy, versioning,
+// encryption and public access block.
+var bucketConfigurations = []bucketConfigurationFunc{
+	bucketLogging,
+	bucketLifecycle,
+	bucketReplication,
+	bucketWebsite,
+	bucketCors,
+	bucketObjectLock,
+	bucketOwnershipControls,
+	bucketACLGrants,
+}
+
+// isErrorCode reports whether err is an S3 API error with one of the given codes. S3 returns an error rather than an
+// empty configuration when a bucket has no lifecycle, replication, website, CORS, Object Lock or ownership controls
+// configuration.
+func isErrorCode(err error, codes ...string) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.ErrorCode() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// int32ToInt converts an optional AWS int32 to the optional int used by the generated types.
+func int32ToInt(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	converted := int(*value)
+	return &converted
+}
+
+func bucketLogging(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bucket) (*methodaws.Bucket, error) {
+	result, err := s3Client.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{Bucket: aws.String(bucket.Name)})
+	if err != nil {
+		return bucket, fmt.Errorf("error getting bucket logging: %v", err)
+	}
+
+	if result.LoggingEnabled != nil {
+		bucket.Logging = &methodaws.S3LoggingConfiguration{
+			TargetBucket: aws.ToString(result.LoggingEnabled.TargetBucket),
+			TargetPrefix: result.LoggingEnabled.TargetPrefix,
+		}
+	}
+	return bucket, nil
+}
+
+func bucketLifecycle(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bucket) (*methodaws.Bucket, error) {
+	result, err := s3Client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: aws.String(bucket.Name)})
+	if err != nil {
+		if isErrorCode(err, "NoSuchLifecycleConfiguration") {
+			return bucket, nil
+		}
+		return bucket, fmt.Errorf("error getting bucket lifecycle configuration: %v", err)
+	}
+
+	lifecycleRules := []*methodaws.S3LifecycleRule{}
+	for _, rule := range result.Rules {
+		lifecycleRule := methodaws.S3LifecycleRule{
+			Id:          rule.ID,
+			Status:      string(rule.Status),
+			Prefix:      lifecycleRulePrefix(rule),
+			Transitions: []*methodaws.S3LifecycleTransition{},
+		}
+		if rule.Expiration != nil {
+			lifecycleRule.ExpirationDays = int32ToInt(rule.Expiration.Days)
+			lifecycleRule.ExpirationDate = rule.Expiration.Date
+		}
+		for _, transition := range rule.Transitions {
+			lifecycleRule.Transitions = append(lifecycleRule.Transitions, &methodaws.S3LifecycleTransition{
+				Days:         int32ToInt(transition.Days),
+				Date:         transition.Date,
+				StorageClass: string(transition.StorageClass),
+			})
+		}
+		if rule.NoncurrentVersionExpiration != nil {
+			lifecycleRule.NoncurrentVersionExpirationDays = int32ToInt(rule.NoncurrentVersionExpiration.NoncurrentDays)
+		}
+		if rule.AbortIncompleteMultipartUpload != nil {
+			lifecycleRule.AbortIncompleteMultipartUploadDays = int32ToInt(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation)
+		}
+		lifecycleRules = append(lifecycleRules, &lifecycleRule)
+	}
+	bucket.LifecycleRules = lifecycleRules
+	return bucket, nil
+}
+
+// lifecycleRulePrefix returns the key prefix a lifecycle rule applies to, from either its filter or its deprecated
+// top-level prefix.
+func lifecycleRulePrefix(rule types.LifecycleRule) *string {
+	switch filter := rule.Filter.(type) {
+	case *types.LifecycleRuleFilterMemberPrefix:
+		return aws.String(filter.Value)
+	case *types.LifecycleRuleFilterMemberAnd:
+		return filter.Value.Prefix
+	}
+	return rule.Prefix
+}
+
+func bucketReplication(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bucket) (*methodaws.Bucket, error) {
+	result, err := s3Client.GetBucketReplication(ctx, &s3.GetBucketReplicationInput{Bucket: aws.String(bucket.Name)})
+	if err != nil {
+		if isErrorCode(err, "ReplicationConfigurationNotFoundError") {
+			return bucket, nil
+		}
+		return bucket, fmt.Errorf("error getting bucket replication configuration: %v", err)
+	}
+	if result.ReplicationConfiguration == nil {
+		return bucket, nil
+	}
+
+	replication := methodaws.S3ReplicationConfiguration{
+		Role:  aws.ToString(result.ReplicationConfiguration.Role),
+		Rules: []*methodaws.S3ReplicationRule{},
+	}
+	for _, rule := range result.ReplicationConfiguration.Rules {
+		replicationRule := methodaws.S3ReplicationRule{
+			Id:       rule.ID,
+			Status:   string(rule.Status),
+			Priority: int32ToInt(rule.Priority),
+			Prefix:   replicationRulePrefix(rule),
+		}
+		if rule.Destination != nil {
+			replicationRule.DestinationBucket = aws.ToString(rule.Destination.Bucket)
+			replicationRule.DestinationAccount = rule.Destination.Account
+			if rule.Destination.StorageClass != "" {
+				replicationRule.StorageClass = aws.String(string(rule.Destination.StorageClass))
+			}
+		}
+		replication.Rules = append(replication.Rules, &replicationRule)
+	}
+	bucket.Replication = &replication
+	return bucket, nil
+}
+
+// replicationRulePrefix returns the key prefix a replication rule applies to, from either its filter or its deprecated
+// top-level prefix.
+func replicationRulePrefix(rule types.ReplicationRule) *string {
+	switch filter := rule.Filter.(type) {
+	case *types.ReplicationRuleFilterMemberPrefix:
+		return aws.String(filter.Value)
+	case *types.ReplicationRuleFilterMemberAnd:
+		return filter.Value.Prefix
+	}
+	return rule.Prefix
+}
+
+func bucketWebsite(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bucket) (*methodaws.Bucket, error) {
+	result, err := s3Client.GetBucketWebsite(ctx, &s3.GetBucketWebsiteInput{Bucket: aws.String(bucket.Name)})
+	if err != nil {
+		if isErrorCode(err, "NoSuchWebsiteConfiguration") {
+			return bucket, nil
+		}
+		return bucket, fmt.Errorf("error getting bucket website configuration: %v", err)
+	}
+
+	website := methodaws.S3WebsiteConfiguration{RoutingRuleCount: len(result.RoutingRules)}
+	if result.IndexDocument != nil {
+		website.IndexDocument = result.IndexDocument.Suffix
+	}
+	if result.ErrorDocument != nil {
+		website.ErrorDocument = result.ErrorDocument.Key
+	}
+	if result.RedirectAllRequestsTo != nil {
+		website.RedirectAllRequestsTo = result.RedirectAllRequestsTo.HostName
+	}
+	bucket.Website = &website
+	return bucket, nil
+}
+
+func bucketCors(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bucket) (*methodaws.Bucket, error) {
+	result, err := s3Client.GetBucketCors(ctx, &s3.GetBucketCorsInput{Bucket: aws.String(bucket.Name)})
+	if err != nil {
+		if isErrorCode(err, "NoSuchCORSConfiguration") {
+			return bucket, nil
+		}
+		return bucket, fmt.Errorf("error getting bucket CORS configuration: %v", err)
+	}
+
+	corsRules := []*methodaws.S3CorsRule{}
+	for _, rule := range result.CORSRules {
+		corsRules = append(corsRules, &methodaws.S3CorsRule{
+			Id:             rule.ID,
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: rule.AllowedHeaders,
+			ExposeHeaders:  rule.ExposeHeaders,
+			MaxAgeSeconds:  int32ToInt(rule.MaxAgeSeconds),
+		})
+	}
+	bucket.CorsRules = corsRules
+	return bucket, nil
+}
+
+func bucketObjectLock(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bucket) (*methodaws.Bucket, error) {
+	result, err := s3Client.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{Bucket: aws.String(bucket.Name)})
+	if err != nil {
+		if isErrorCode(err, "ObjectLockConfigurationNotFoundError") {
+			bucket.ObjectLock = &methodaws.S3ObjectLockConfiguration{Enabled: false}
+			return bucket, nil
+		}
+		return bucket, fmt.Errorf("error getting bucket Object Lock configuration: %v", err)
+	}
+
+	objectLock := methodaws.S3ObjectLockConfiguration{}
+	if result.ObjectLockConfiguration != nil {
+		configuration := result.ObjectLockConfiguration
+		objectLock.Enabled = configuration.ObjectLockEnabled == types.ObjectLockEnabledEnabled
+		if configuration.Rule != nil && configuration.Rule.DefaultRetention != nil {
+			retention := configuration.Rule.DefaultRetention
+			if mode, err := methodaws.NewS3ObjectLockRetentionModeFromString(string(retention.Mode)); err == nil {
+				objectLock.Mode = &mode
+			}
+			objectLock.Days = int32ToInt(retention.Days)
+			objectLock.Years = int32ToInt(retention.Years)
+		}
+	}
+	bucket.ObjectLock = &objectLock
+	return bucket, nil
+}
+
+func bucketOwnershipControls(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bucket) (*methodaws.Bucket, error) {
+	result, err := s3Client.GetBucketOwnershipControls(ctx, &s3.GetBucketOwnershipControlsInput{Bucket: aws.String(bucket.Name)})
+	if err != nil {
+		if isErrorCode(err, "OwnershipControlsNotFoundError") {
+			return bucket, nil
+		}
+		return bucket, fmt.Errorf("error getting bucket ownership controls: %v", err)
+	}
+
+	if result.OwnershipControls != nil {
+		for _, rule := range result.OwnershipControls.Rules {
+			if ownership, err := methodaws.NewS3ObjectOwnershipFromString(string(rule.ObjectOwnership)); err == nil {
+				bucket.ObjectOwnership = &ownership
+			}
+		}
+	}
+	return bucket, nil
+}
+
+func bucketACLGrants(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bucket) (*methodaws.Bucket, error) {
+	result, err := s3Client.GetBucketAcl(ctx, &s3.GetBucketAclInput{Bucket: aws.String(bucket.Name)})
+	if err != nil {
+		return bucket, fmt.Errorf("error getting bucket ACL: %v", err)
+	}
+
+	grants := []*methodaws.S3BucketGrant{}
+	for _, grant := range result.Grants {
+		if grant.Grantee == nil {
+			continue
+		}
+		granteeType, err := methodaws.NewS3GranteeTypeFromString(string(grant.Grantee.Type))
+		if err != nil {
+			continue
+		}
+		grants = append(grants, &methodaws.S3BucketGrant{
+			GranteeType:  granteeType,
+			GranteeId:    grant.Grantee.ID,
+			GranteeName:  grant.Grantee.DisplayName,
+			GranteeEmail: grant.Grantee.EmailAddress,
+			GranteeUri:   grant.Grantee.URI,
+			Permission:   string(grant.Permission),
+		})
+	}
+	bucket.AclGrants = grants
+	return bucket, nil
+}
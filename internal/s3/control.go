@@ -0,0 +1,168 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+
+	methodaws "github.com/Method-Security/methodaws/generated/go"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	"github.com/aws/aws-sdk-go-v2/service/s3control/types"
+)
+
+// multiRegionAccessPointRegion is the only region that serves the Multi-Region Access Point control plane.
+const multiRegionAccessPointRegion = "us-west-2"
+
+// toPublicAccessBlockConfiguration converts an S3 Control Public Access Block, treating unset fields as false.
+func toPublicAccessBlockConfiguration(configuration *types.PublicAccessBlockConfiguration) *methodaws.S3PublicAccessBlockConfiguration {
+	if configuration == nil {
+		return nil
+	}
+	return &methodaws.S3PublicAccessBlockConfiguration{
+		BlockPublicAcls:       aws.ToBool(configuration.BlockPublicAcls),
+		IgnorePublicAcls:      aws.ToBool(configuration.IgnorePublicAcls),
+		BlockPublicPolicy:     aws.ToBool(configuration.BlockPublicPolicy),
+		RestrictPublicBuckets: aws.ToBool(configuration.RestrictPublicBuckets),
+	}
+}
+
+// accountPublicAccess returns the account-level Public Access Block, or nil if the account has none.
+func accountPublicAccess(ctx context.Context, cfg aws.Config, accountID string) (*methodaws.S3PublicAccessBlockConfiguration, error) {
+	client := s3control.NewFromConfig(cfg)
+	result, err := client.GetPublicAccessBlock(ctx, &s3control.GetPublicAccessBlockInput{AccountId: aws.String(accountID)})
+	if err != nil {
+		if isErrorCode(err, "NoSuchPublicAccessBlockConfiguration") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting account public access block: %v", err)
+	}
+	return toPublicAccessBlockConfiguration(result.PublicAccessBlockConfiguration), nil
+}
+
+// effectivePublicAccess combines the account-level and bucket-level Public Access Blocks. S3 applies the most
+// restrictive combination, so a setting is in effect if it is enabled at either level.
+func effectivePublicAccess(account *methodaws.S3PublicAccessBlockConfiguration, bucket *methodaws.S3PublicAccessBlockConfiguration) *methodaws.S3PublicAccessBlockConfiguration {
+	effective := &methodaws.S3PublicAccessBlockConfiguration{}
+	for _, configuration := range []*methodaws.S3PublicAccessBlockConfiguration{account, bucket} {
+		if configuration == nil {
+			continue
+		}
+		effective.BlockPublicAcls = effective.BlockPublicAcls || configuration.BlockPublicAcls
+		effective.IgnorePublicAcls = effective.IgnorePublicAcls || configuration.IgnorePublicAcls
+		effective.BlockPublicPolicy = effective.BlockPublicPolicy || configuration.BlockPublicPolicy
+		effective.RestrictPublicBuckets = effective.RestrictPublicBuckets || configuration.RestrictPublicBuckets
+	}
+	return effective
+}
+
+// listAccessPoints lists the access points of a region alongside their network origin, Public Access Block and
+// policy. Errors fetching an access point's details are recorded on the access point.
+func listAccessPoints(ctx context.Context, cfg aws.Config, accountID string, region string) ([]*methodaws.S3AccessPoint, error) {
+	cfg.Region = region
+	client := s3control.NewFromConfig(cfg)
+	accessPoints := []*methodaws.S3AccessPoint{}
+
+	paginator := s3control.NewListAccessPointsPaginator(client, &s3control.ListAccessPointsInput{AccountId: aws.String(accountID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return accessPoints, fmt.Errorf("error listing access points: %v", err)
+		}
+
+		for _, item := range page.AccessPointList {
+			accessPoint := &methodaws.S3AccessPoint{
+				Name:            aws.ToString(item.Name),
+				Arn:             aws.ToString(item.AccessPointArn),
+				Alias:           item.Alias,
+				Region:          region,
+				Bucket:          aws.ToString(item.Bucket),
+				BucketAccountId: item.BucketAccountId,
+				Errors:          []string{},
+			}
+			networkOrigin, err := methodaws.NewS3NetworkOriginFromString(string(item.NetworkOrigin))
+			if err != nil {
+				accessPoint.Errors = append(accessPoint.Errors, fmt.Sprintf("error parsing access point network origin: %v", err))
+			} else {
+				accessPoint.NetworkOrigin = networkOrigin
+			}
+			if item.VpcConfiguration != nil {
+				accessPoint.VpcId = item.VpcConfiguration.VpcId
+			}
+
+			details, err := client.GetAccessPoint(ctx, &s3control.GetAccessPointInput{AccountId: aws.String(accountID), Name: item.Name})
+			if err != nil {
+				accessPoint.Errors = append(accessPoint.Errors, fmt.Sprintf("error getting access point: %v", err))
+			} else {
+				accessPoint.CreationDate = details.CreationDate
+				accessPoint.PublicAccessConfig = toPublicAccessBlockConfiguration(details.PublicAccessBlockConfiguration)
+			}
+
+			policy, err := client.GetAccessPointPolicy(ctx, &s3control.GetAccessPointPolicyInput{AccountId: aws.String(accountID), Name: item.Name})
+			if err != nil {
+				if !isErrorCode(err, "NoSuchAccessPointPolicy") {
+					accessPoint.Errors = append(accessPoint.Errors, fmt.Sprintf("error getting access point policy: %v", err))
+				}
+			} else {
+				accessPoint.Policy = policy.Policy
+				status, err := client.GetAccessPointPolicyStatus(ctx, &s3control.GetAccessPointPolicyStatusInput{AccountId: aws.String(accountID), Name: item.Name})
+				if err != nil {
+					accessPoint.Errors = append(accessPoint.Errors, fmt.Sprintf("error getting access point policy status: %v", err))
+				} else if status.PolicyStatus != nil {
+					accessPoint.PolicyIsPublic = aws.Bool(status.PolicyStatus.IsPublic)
+				}
+			}
+
+			accessPoints = append(accessPoints, accessPoint)
+		}
+	}
+
+	return accessPoints, nil
+}
+
+// listMultiRegionAccessPoints lists the account's Multi-Region Access Points alongside the buckets they route to and
+// their established policy. Errors fetching a policy are recorded on the access point.
+func listMultiRegionAccessPoints(ctx context.Context, cfg aws.Config, accountID string) ([]*methodaws.S3MultiRegionAccessPoint, error) {
+	cfg.Region = multiRegionAccessPointRegion
+	client := s3control.NewFromConfig(cfg)
+	accessPoints := []*methodaws.S3MultiRegionAccessPoint{}
+
+	paginator := s3control.NewListMultiRegionAccessPointsPaginator(client, &s3control.ListMultiRegionAccessPointsInput{AccountId: aws.String(accountID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return accessPoints, fmt.Errorf("error listing multi-region access points: %v", err)
+		}
+
+		for _, item := range page.AccessPoints {
+			accessPoint := &methodaws.S3MultiRegionAccessPoint{
+				Name:               aws.ToString(item.Name),
+				Alias:              item.Alias,
+				Status:             string(item.Status),
+				CreatedAt:          item.CreatedAt,
+				PublicAccessConfig: toPublicAccessBlockConfiguration(item.PublicAccessBlock),
+				Regions:            []*methodaws.S3MultiRegionAccessPointRegion{},
+				Errors:             []string{},
+			}
+			for _, region := range item.Regions {
+				accessPoint.Regions = append(accessPoint.Regions, &methodaws.S3MultiRegionAccessPointRegion{
+					Bucket:          aws.ToString(region.Bucket),
+					BucketAccountId: region.BucketAccountId,
+					Region:          aws.ToString(region.Region),
+				})
+			}
+
+			policy, err := client.GetMultiRegionAccessPointPolicy(ctx, &s3control.GetMultiRegionAccessPointPolicyInput{AccountId: aws.String(accountID), Name: item.Name})
+			if err != nil {
+				if !isErrorCode(err, "NoSuchMultiRegionAccessPointPolicy") {
+					accessPoint.Errors = append(accessPoint.Errors, fmt.Sprintf("error getting multi-region access point policy: %v", err))
+				}
+			} else if policy.Policy != nil && policy.Policy.Established != nil {
+				accessPoint.Policy = policy.Policy.Established.Policy
+			}
+
+			accessPoints = append(accessPoints, accessPoint)
+		}
+	}
+
+	return accessPoints, nil
+}
@@ -0,0 +1,172 @@
+package s3
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	methodaws "github.com/Method-Security/methodaws/generated/go"
+)
+
+// discoveryWorkers is the number of candidate bucket names that are checked concurrently
+const discoveryWorkers = 32
+
+// defaultDiscoveryWords are combined with the seeds when no wordlist is provided.
+var defaultDiscoveryWords = []string{
+	"assets", "backup", "backups", "bucket", "cdn", "data", "dev", "development", "docs", "files", "images", "internal",
+	"logs", "media", "private", "prod", "production", "public", "qa", "staging", "static", "storage", "test", "uploads",
+	"web", "www",
+}
+
+// discoverySeparators join a seed and a word when generating permutations.
+var discoverySeparators = []string{"-", ".", ""}
+
+var (
+	bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+	ipAddressPattern  = regexp.MustCompile(`^\d+\.\d+\.\d+\.\d+$`)
+	seedTokenPattern  = regexp.MustCompile(`[a-z0-9]+`)
+)
+
+// LoadWordlist reads a wordlist with one word per line. Blank lines and lines starting with # are ignored.
+func LoadWordlist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening wordlist: %v", err)
+	}
+	defer file.Close()
+
+	words := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading wordlist: %v", err)
+	}
+	return words, nil
+}
+
+// validBucketName reports whether a name follows the S3 bucket naming rules.
+func validBucketName(name string) bool {
+	if !bucketNamePattern.MatchString(name) || ipAddressPattern.MatchString(name) {
+		return false
+	}
+	return !strings.Contains(name, "..") && !strings.Contains(name, ".-") && !strings.Contains(name, "-.")
+}
+
+// seedVariants returns the forms of a seed used as the base of permutations. A company name or domain such as
+// "Example Corp" or "example.com" yields the seed as given, its words joined directly and with dashes, and its first
+// word on its own.
+func seedVariants(seed string) []string {
+	seed = strings.ToLower(strings.TrimSpace(seed))
+	tokens := seedTokenPattern.FindAllString(seed, -1)
+	if len(tokens) == 0 {
+		return nil
+	}
+	return []string{seed, strings.Join(tokens, ""), strings.Join(tokens, "-"), tokens[0]}
+}
+
+// GenerateBucketNames generates candidate bucket names from the seeds and words. Each seed variant is used on its own
+// and combined with every word as a prefix and a suffix. Invalid bucket names and duplicates are dropped, and the
+// order of the candidates is stable.
+func GenerateBucketNames(seeds []string, words []string) []string {
+	if len(words) == 0 {
+		words = defaultDiscoveryWords
+	}
+
+	names := []string{}
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] && validBucketName(name) {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, seed := range seeds {
+		for _, variant := range seedVariants(seed) {
+			add(variant)
+			for _, word := range words {
+				for _, separator := range discoverySeparators {
+					add(variant + separator + word)
+					add(word + separator + variant)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// discoverBucket locates a single candidate name and enumerates it with anonymous credentials if it exists. A bucket
+// that denies the anonymous request exists; only a 404 response means that the candidate does not, see headBucket.
+func discoverBucket(ctx context.Context, bucketName string, options ExternalOptions) methodaws.ExternalS3Report {
+	report := methodaws.ExternalS3Report{
+		ExternalBuckets: []*methodaws.ExternalBucket{},
+		NotFoundBuckets: []string{},
+		Errors:          []string{},
+	}
+	region, exists, err := locateBucket(ctx, bucketName)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("Error locating bucket %s: %v", bucketName, err))
+		return report
+	}
+	if !exists {
+		report.NotFoundBuckets = append(report.NotFoundBuckets, bucketName)
+		return report
+	}
+	return ExternalEnumerateS3Region(ctx, report, bucketName, region, options)
+}
+
+// DiscoverExternalS3 generates bucket name permutations from the seeds and words and checks each of them anonymously,
+// reporting the buckets that exist alongside whether listing and reading are allowed, and the candidates that do not
+// exist. Candidates are checked concurrently by a bounded pool of workers. The write checks are never run on discovered
+// buckets, as they may belong to anyone.
+func DiscoverExternalS3(ctx context.Context, seeds []string, words []string, options ExternalOptions) methodaws.ExternalS3Report {
+	options.CheckWrite = false
+	report := methodaws.ExternalS3Report{
+		ExternalBuckets: []*methodaws.ExternalBucket{},
+		NotFoundBuckets: []string{},
+		Errors:          []string{},
+	}
+	report = callerAccountID(ctx, report, options)
+
+	candidates := GenerateBucketNames(seeds, words)
+	if len(candidates) == 0 {
+		report.Errors = append(report.Errors, "No valid bucket names could be generated from the provided seeds")
+		return report
+	}
+
+	// Keep the results in the order the candidates were generated
+	results := make([]methodaws.ExternalS3Report, len(candidates))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < discoveryWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = discoverBucket(ctx, candidates[i], options)
+			}
+		}()
+	}
+	for i := range candidates {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, result := range results {
+		report.ExternalBuckets = append(report.ExternalBuckets, result.ExternalBuckets...)
+		report.NotFoundBuckets = append(report.NotFoundBuckets, result.NotFoundBuckets...)
+		report.Errors = append(report.Errors, result.Errors...)
+	}
+	return report
+}
@@ -2,49 +2,116 @@ package s3
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	methodaws "github.com/Method-Security/methodaws/generated/go"
+	"github.com/Method-Security/methodaws/internal/sts"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
-func bucketExists(ctx context.Context, region string, bucketName string) (bool, error) {
-	// Create a custom AWS config with anonymous credentials
+const (
+	// bucketRegionHeader is the response header in which S3 returns a bucket's region
+	bucketRegionHeader = "x-amz-bucket-region"
+
+	// canaryKeyPrefix and canaryBody label the object uploaded by the anonymous write check
+	canaryKeyPrefix = "methodaws-write-check-"
+	canaryBody      = "This object was uploaded by methodaws to test anonymous write access and should have been deleted automatically."
+)
+
+// ExternalOptions controls the optional checks run against external buckets.
+type ExternalOptions struct {
+	// CheckWrite enables the anonymous write checks, which upload and delete a canary object and re-apply the bucket's
+	// ACL. These modify the target bucket and are off by default.
+	CheckWrite bool
+
+	// AuthenticatedConfig, when set, repeats the read-only checks with these credentials to find buckets that are
+	// exposed to any authenticated AWS user. The credentials should belong to an account other than the bucket owner's.
+	AuthenticatedConfig *aws.Config
+}
+
+// callerAccountID records the account of the authenticated credentials on the report, if there are any.
+func callerAccountID(ctx context.Context, report methodaws.ExternalS3Report, options ExternalOptions) methodaws.ExternalS3Report {
+	if options.AuthenticatedConfig == nil {
+		return report
+	}
+	accountID, err := sts.GetAccountID(ctx, *options.AuthenticatedConfig)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("Error getting caller account ID: %v", err))
+		return report
+	}
+	report.CallerAccountId = accountID
+	return report
+}
+
+// anonymousClient creates an S3 client for a region that signs no requests.
+func anonymousClient(ctx context.Context, region string) (*s3.Client, error) {
 	cfg, err := config.LoadDefaultConfig(ctx,
 		config.WithRegion(region),
 		config.WithCredentialsProvider(aws.AnonymousCredentials{}),
 	)
 	if err != nil {
-		return false, fmt.Errorf("error loading AWS config: %v", err)
+		return nil, fmt.Errorf("error loading AWS config: %v", err)
 	}
+	return s3.NewFromConfig(cfg), nil
+}
 
-	// Create an S3 client
-	client := s3.NewFromConfig(cfg)
-
-	// Call HeadBucket operation
-	_, err = client.HeadBucket(ctx, &s3.HeadBucketInput{
+// headBucket sends an anonymous HeadBucket request and classifies the bucket from the response. S3 returns the bucket's
+// region in the x-amz-bucket-region header whether the request succeeds, is denied or is redirected to another region,
+// so the region is returned for any bucket that exists. A redirect does not reveal whether access is allowed, in which
+// case the returned status is empty. The boolean is false if the bucket does not exist.
+func headBucket(ctx context.Context, client *s3.Client, bucketName string) (methodaws.ExternalBucketStatus, string, bool, error) {
+	output, err := client.HeadBucket(ctx, &s3.HeadBucketInput{
 		Bucket: aws.String(bucketName),
 	})
+	if err == nil {
+		return methodaws.ExternalBucketStatusExists, aws.ToString(output.BucketRegion), true, nil
+	}
 
-	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			switch aerr.Code() {
-			case "NotFound":
-				return false, nil
-			case "Forbidden":
-				return true, nil
-			default:
-				return false, fmt.Errorf("error checking bucket: %v", err)
-			}
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return "", "", false, nil
+	}
+	var responseErr *awshttp.ResponseError
+	if !errors.As(err, &responseErr) {
+		return "", "", false, fmt.Errorf("error checking bucket: %v", err)
+	}
+	region := responseErr.Response.Header.Get(bucketRegionHeader)
+	switch responseErr.HTTPStatusCode() {
+	case http.StatusNotFound:
+		return "", "", false, nil
+	case http.StatusForbidden:
+		return methodaws.ExternalBucketStatusAccessDenied, region, true, nil
+	case http.StatusMovedPermanently, http.StatusBadRequest:
+		if region != "" {
+			return "", region, true, nil
 		}
-		return false, fmt.Errorf("error checking bucket: %v", err)
 	}
+	return "", "", false, fmt.Errorf("error checking bucket: %v", err)
+}
 
-	// If there's no error, the bucket exists
-	return true, nil
+// locateBucket finds the region of a bucket with a single anonymous request to the global endpoint. The boolean is
+// false if the bucket does not exist.
+func locateBucket(ctx context.Context, bucketName string) (string, bool, error) {
+	client, err := anonymousClient(ctx, locationRegion)
+	if err != nil {
+		return "", false, err
+	}
+	_, region, exists, err := headBucket(ctx, client, bucketName)
+	if err != nil || !exists {
+		return "", false, err
+	}
+	if region == "" {
+		return "", false, fmt.Errorf("error checking bucket: no %s header in response", bucketRegionHeader)
+	}
+	return region, true, nil
 }
 
 // listBucketContents lists all objects in a bucket
@@ -141,38 +208,114 @@ func checkACL(ctx context.Context, client *s3.Client, bucketName string) ([]*met
 	return acls, nil
 }
 
+// checkPolicyStatus checks whether S3 considers the bucket policy public
+func checkPolicyStatus(ctx context.Context, client *s3.Client, bucketName string) (*bool, error) {
+	statusOutput, err := client.GetBucketPolicyStatus(ctx, &s3.GetBucketPolicyStatusInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting bucket policy status: %v", err)
+	}
+	if statusOutput.PolicyStatus == nil {
+		return nil, nil
+	}
+	return statusOutput.PolicyStatus.IsPublic, nil
+}
+
+// checkWrite tests whether anonymous writes are allowed by uploading a canary object and deleting it again, and whether
+// the bucket ACL can be modified by re-applying the current ACL unchanged. The ACL is only re-applied if it can be read,
+// so that the check never alters it. These checks modify the bucket and only run when requested. Only an AccessDenied
+// response means that a write is not allowed; other failures are returned as errors and leave the result unset.
+func checkWrite(ctx context.Context, client *s3.Client, bucketName string) (*methodaws.ExternalBucketWriteCheck, []string) {
+	writeErrors := []string{}
+	writeCheck := &methodaws.ExternalBucketWriteCheck{
+		CanaryKey: fmt.Sprintf("%s%d.txt", canaryKeyPrefix, time.Now().UnixNano()),
+	}
+
+	_, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(writeCheck.CanaryKey),
+		Body:   strings.NewReader(canaryBody),
+	})
+	writeCheck.AllowAnonymousWrite, err = writeAllowed(err)
+	if err != nil {
+		writeErrors = append(writeErrors, fmt.Sprintf("Error uploading canary object %s to bucket %s: %v", writeCheck.CanaryKey, bucketName, err))
+	}
+	if aws.ToBool(writeCheck.AllowAnonymousWrite) {
+		_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(writeCheck.CanaryKey),
+		})
+		writeCheck.CanaryDeleted = err == nil
+		if err != nil {
+			writeErrors = append(writeErrors, fmt.Sprintf("Error deleting canary object %s from bucket %s, it must be removed manually: %v", writeCheck.CanaryKey, bucketName, err))
+		}
+	}
+
+	aclOutput, err := client.GetBucketAcl(ctx, &s3.GetBucketAclInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err == nil {
+		_, err = client.PutBucketAcl(ctx, &s3.PutBucketAclInput{
+			Bucket: aws.String(bucketName),
+			AccessControlPolicy: &types.AccessControlPolicy{
+				Grants: aclOutput.Grants,
+				Owner:  aclOutput.Owner,
+			},
+		})
+		writeCheck.AllowAclWrite, err = writeAllowed(err)
+		if err != nil {
+			writeErrors = append(writeErrors, fmt.Sprintf("Error re-applying the ACL of bucket %s: %v", bucketName, err))
+		}
+	}
+
+	return writeCheck, writeErrors
+}
+
+// writeAllowed interprets the error of a write request: no error means the write is allowed and AccessDenied means it
+// is not. Any other error leaves the result unknown and is returned.
+func writeAllowed(err error) (*bool, error) {
+	if err == nil {
+		return aws.Bool(true), nil
+	}
+	if isErrorCode(err, "AccessDenied") {
+		return aws.Bool(false), nil
+	}
+	return nil, err
+}
+
 // ExternalEnumerateS3Region enumerates a single public facing S3 bucket in a specific region.
 // If the bucket does not exist, it will return an unmodified report (with potential new errors).
-func ExternalEnumerateS3Region(ctx context.Context, report methodaws.ExternalS3Report, bucketName string, region string) methodaws.ExternalS3Report {
+func ExternalEnumerateS3Region(ctx context.Context, report methodaws.ExternalS3Report, bucketName string, region string, options ExternalOptions) methodaws.ExternalS3Report {
+	// Create an S3 client with anonymous credentials
+	client, err := anonymousClient(ctx, region)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+
 	// Check if bucket exists before proceeding
-	exists, err := bucketExists(ctx, region, bucketName)
+	status, _, exists, err := headBucket(ctx, client, bucketName)
 	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("Error checking bucket %s: %v", bucketName, err))
 		return report
 	}
 	if !exists {
 		return report
 	}
-
-	// Enumerate the bucket
-	externalBucket := methodaws.ExternalBucket{}
-
-	// Create a custom AWS config with anonymous credentials
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion(region),
-		config.WithCredentialsProvider(aws.AnonymousCredentials{}),
-	)
-	if err != nil {
-		report.Errors = append(report.Errors, fmt.Sprintf("error loading AWS config: %v", err))
+	if status == "" {
+		report.Errors = append(report.Errors, fmt.Sprintf("Bucket %s is not in region %s", bucketName, region))
 		return report
 	}
 
-	// Create an S3 client
-	client := s3.NewFromConfig(cfg)
+	// Enumerate the bucket
+	externalBucket := methodaws.ExternalBucket{}
 
 	// Populate basic information
 	externalBucket.Name = bucketName
-	externalBucket.Url = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucketName, cfg.Region)
+	externalBucket.Url = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucketName, region)
 	externalBucket.Region = region
+	externalBucket.Status = status
 
 	// List bucket contents
 	directoryContents, err := listBucketContents(ctx, client, bucketName)
@@ -198,41 +341,59 @@ func ExternalEnumerateS3Region(ctx context.Context, report methodaws.ExternalS3R
 
 	// Check bucket ACL
 	acls, err := checkACL(ctx, client, bucketName)
+	externalBucket.AllowAclRead = err == nil
 	if err == nil {
 		externalBucket.Acls = acls
 	} else {
 		report.Errors = append(report.Errors, fmt.Sprintf("Error getting bucket ACL: %v", err))
 	}
 
+	// Check bucket policy status
+	policyIsPublic, err := checkPolicyStatus(ctx, client, bucketName)
+	if err == nil {
+		externalBucket.PolicyIsPublic = policyIsPublic
+	} else {
+		report.Errors = append(report.Errors, fmt.Sprintf("Error getting bucket policy status: %v", err))
+	}
+
+	// Check anonymous writes, which modify the bucket, only when requested
+	if options.CheckWrite {
+		writeCheck, writeErrors := checkWrite(ctx, client, bucketName)
+		externalBucket.WriteCheck = writeCheck
+		report.Errors = append(report.Errors, writeErrors...)
+	}
+
+	// Repeat the read-only checks as an authenticated user from another account, only when requested
+	if options.AuthenticatedConfig != nil {
+		authenticatedAccess, authenticatedErrors := checkAuthenticatedAccess(ctx, *options.AuthenticatedConfig, bucketName, region, &externalBucket)
+		externalBucket.AuthenticatedAccess = authenticatedAccess
+		report.Errors = append(report.Errors, authenticatedErrors...)
+	}
+
 	report.ExternalBuckets = append(report.ExternalBuckets, &externalBucket)
 	return report
 }
 
-// ExternalEnumerateS3 attempts to enumerate a public facing S3 bucket with no credentials.
-// It checks regions one by one until it finds the bucket or exhausts all regions.
-func ExternalEnumerateS3(ctx context.Context, bucketName string, regions []string) methodaws.ExternalS3Report {
+// ExternalEnumerateS3 attempts to enumerate a public facing S3 bucket with no credentials. The bucket's region is read
+// from the x-amz-bucket-region header of a single request, and a bucket that does not exist is listed in the report's
+// NotFoundBuckets. With authenticated credentials, the read-only checks are repeated as the caller as well.
+func ExternalEnumerateS3(ctx context.Context, bucketName string, options ExternalOptions) methodaws.ExternalS3Report {
 	report := methodaws.ExternalS3Report{
 		ExternalBuckets: []*methodaws.ExternalBucket{},
+		NotFoundBuckets: []string{},
 		Errors:          []string{},
 	}
+	report = callerAccountID(ctx, report, options)
 
-	for _, region := range regions {
-		exists, err := bucketExists(ctx, region, bucketName)
-		if err != nil {
-			report.Errors = append(report.Errors, fmt.Sprintf("Error checking bucket in region %s: %v", region, err))
-			continue
-		}
-		if exists {
-			r := ExternalEnumerateS3Region(ctx, report, bucketName, region)
-			report.Errors = append(report.Errors, r.Errors...)
-			report.ExternalBuckets = append(report.ExternalBuckets, r.ExternalBuckets...)
-			break // Stop checking other regions once we find the bucket
-		}
+	region, exists, err := locateBucket(ctx, bucketName)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("Error locating bucket %s: %v", bucketName, err))
+		return report
 	}
-
-	if len(report.ExternalBuckets) == 0 {
-		report.Errors = append(report.Errors, "Bucket not found in any of the specified regions")
+	if !exists {
+		report.NotFoundBuckets = append(report.NotFoundBuckets, bucketName)
+		return report
 	}
 
-	return report
+	return ExternalEnumerateS3Region(ctx, report, bucketName, region, options)
 }
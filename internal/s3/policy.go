@@ -0,0 +1,50 @@
+package s3
+
+import (
+	"sort"
+
+	methodaws "github.com/Method-Security/methodaws/generated/go"
+	"github.com/Method-Security/methodaws/internal/iam/policy"
+)
+
+// statementConditions flattens the condition block of a statement into one condition per operator and key, sorted so
+// that the output is stable.
+func statementConditions(statement policy.Statement) []*methodaws.S3PolicyCondition {
+	conditions := []*methodaws.S3PolicyCondition{}
+	for operator, keys := range statement.Condition {
+		for key, values := range keys {
+			conditions = append(conditions, &methodaws.S3PolicyCondition{
+				Operator: operator,
+				Key:      key,
+				Values:   values,
+			})
+		}
+	}
+	sort.Slice(conditions, func(i, j int) bool {
+		if conditions[i].Operator != conditions[j].Operator {
+			return conditions[i].Operator < conditions[j].Operator
+		}
+		return conditions[i].Key < conditions[j].Key
+	})
+	return conditions
+}
+
+// toS3PolicyStatement converts a parsed statement into its generated representation.
+func toS3PolicyStatement(statement policy.Statement) *methodaws.S3PolicyStatement {
+	s3Statement := &methodaws.S3PolicyStatement{
+		Effect:       statement.Effect,
+		Principals:   statement.Principal.Principals(),
+		Actions:      statement.Action,
+		NotActions:   statement.NotAction,
+		Resources:    statement.Resource,
+		NotResources: statement.NotResource,
+		Conditions:   statementConditions(statement),
+	}
+	if statement.Sid != "" {
+		s3Statement.Sid = &statement.Sid
+	}
+	if statement.NotPrincipal != nil {
+		s3Statement.NotPrincipals = statement.NotPrincipal.Principals()
+	}
+	return s3Statement
+}
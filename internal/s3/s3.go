@@ -4,14 +4,51 @@ package s3
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	methodaws "github.com/Method-Security/methodaws/generated/go"
 	"github.com/Method-Security/methodaws/internal/sts"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
+const (
+	// bucketWorkers is the number of buckets that are enriched concurrently
+	bucketWorkers = 16
+
+	// locationRegion is the region used to list buckets and look up their locations. Buckets in us-east-1 report an
+	// empty location constraint.
+	locationRegion = "us-east-1"
+)
+
+// clientCache shares one S3 client per region between the workers enriching buckets.
+type clientCache struct {
+	cfg     aws.Config
+	mu      sync.Mutex
+	clients map[string]*s3.Client
+}
+
+func newClientCache(cfg aws.Config) *clientCache {
+	return &clientCache{cfg: cfg, clients: map[string]*s3.Client{}}
+}
+
+// forRegion returns the client for a region, creating it on first use.
+func (c *clientCache) forRegion(region string) *s3.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	client, ok := c.clients[region]
+	if !ok {
+		regionCfg := c.cfg.Copy()
+		regionCfg.Region = region
+		client = s3.NewFromConfig(regionCfg)
+		c.clients[region] = client
+	}
+	return client
+}
+
 func publicAccess(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bucket) (*methodaws.Bucket, error) {
 	input := &s3.GetPublicAccessBlockInput{
 		Bucket: aws.String(bucket.Name),
@@ -82,7 +119,10 @@ func bucketPolicy(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bu
 
 	result, err := s3Client.GetBucketPolicy(ctx, &input)
 	if err != nil {
-		return bucket, err
+		if isErrorCode(err, "NoSuchBucketPolicy") {
+			return bucket, nil
+		}
+		return bucket, fmt.Errorf("error getting bucket policy: %v", err)
 	}
 
 	bucket.Policy = result.Policy
@@ -90,11 +130,105 @@ func bucketPolicy(ctx context.Context, s3Client *s3.Client, bucket *methodaws.Bu
 	return bucket, nil
 }
 
-// EnumerateS3 retrieves all S3 buckets available to the caller and returns an EnumerateResourceReport struct. Non-fatal
-// errors that occur during the execution of the `methodaws s3 enumerate` subcommand are included in the report, but
-// the function will not return an error unless there is an issue retrieving the account ID.
+// enrichBucket fetches the region and configuration of a single bucket and evaluates its access. It returns the errors
+// that belong in the report-level errors; errors fetching the remaining configuration are recorded on the bucket. The
+// bucket is nil if its region cannot be determined.
+func enrichBucket(ctx context.Context, clients *clientCache, bucket types.Bucket, owner *types.Owner, accountID string, accountPublicAccessConfig *methodaws.S3PublicAccessBlockConfiguration) (*methodaws.Bucket, []string) {
+	errorMessages := []string{}
+	s3Bucket := &methodaws.Bucket{
+		CreationDate: aws.ToTime(bucket.CreationDate),
+		Name:         aws.ToString(bucket.Name),
+	}
+	if owner != nil {
+		s3Bucket.OwnerId = aws.ToString(owner.ID)
+		s3Bucket.OwnerName = aws.ToString(owner.DisplayName)
+	}
+
+	// Get the bucket's region
+	regionOutput, err := clients.forRegion(locationRegion).GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: bucket.Name})
+	if err != nil {
+		errorMessages = append(errorMessages, fmt.Sprintf("Error getting location for bucket %s: %v", *bucket.Name, err))
+		return nil, errorMessages
+	}
+	s3Bucket.Region = string(regionOutput.LocationConstraint)
+	if s3Bucket.Region == "" {
+		s3Bucket.Region = locationRegion
+	}
+
+	bucketClient := clients.forRegion(s3Bucket.Region)
+
+	// Fetch additional bucket details
+	s3Bucket, err = objectVersioning(ctx, bucketClient, s3Bucket)
+	if err != nil {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	s3Bucket, err = bucketEncryption(ctx, bucketClient, s3Bucket)
+	if err != nil {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	s3Bucket, err = publicAccess(ctx, bucketClient, s3Bucket)
+	if err != nil {
+		errorMessages = append(errorMessages, err.Error())
+	}
+	s3Bucket.EffectivePublicAccessConfig = effectivePublicAccess(accountPublicAccessConfig, s3Bucket.PublicAccessConfig)
+
+	// Errors fetching the policy and the remaining configuration are recorded on the bucket they belong to, and a policy
+	// that cannot be fetched leaves the access analysis incomplete
+	s3Bucket.Errors = []string{}
+	s3Bucket, err = bucketPolicy(ctx, bucketClient, s3Bucket)
+	policyKnown := err == nil
+	if err != nil {
+		s3Bucket.Errors = append(s3Bucket.Errors, err.Error())
+	}
+	for _, configuration := range bucketConfigurations {
+		s3Bucket, err = configuration(ctx, bucketClient, s3Bucket)
+		if err != nil {
+			s3Bucket.Errors = append(s3Bucket.Errors, err.Error())
+		}
+	}
+
+	s3Bucket.AccessAnalysis, err = analyzeBucketAccess(s3Bucket, accountID, policyKnown)
+	if err != nil {
+		s3Bucket.Errors = append(s3Bucket.Errors, err.Error())
+	}
+
+	s3Bucket.Url = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", *bucket.Name, s3Bucket.Region)
+
+	bucketARN := arn.ARN{
+		Partition: "aws",
+		Service:   "s3",
+		Resource:  *bucket.Name,
+	}
+	s3Bucket.Arn = bucketARN.String()
+
+	return s3Bucket, errorMessages
+}
+
+// matchesBucketFilter reports whether a bucket should be enumerated. A bucket matches when it is one of the named
+// buckets (if any are named) and starts with the prefix (if one is given).
+func matchesBucketFilter(name string, bucketNames []string, bucketPrefix string) bool {
+	if len(bucketNames) > 0 {
+		named := false
+		for _, bucketName := range bucketNames {
+			if bucketName == name {
+				named = true
+			}
+		}
+		if !named {
+			return false
+		}
+	}
+	return strings.HasPrefix(name, bucketPrefix)
+}
 
-func EnumerateS3(ctx context.Context, cfg aws.Config, regions []string) methodaws.S3Report {
+// EnumerateS3 retrieves the S3 buckets available to the caller and returns an EnumerateResourceReport struct. The
+// buckets can be limited to a set of names and to names starting with a prefix; an empty filter matches every bucket.
+// Buckets are enriched concurrently by a bounded pool of workers. Non-fatal errors that occur during the execution of
+// the `methodaws s3 enumerate` subcommand are included in the report, but the function will not return an error
+// unless there is an issue retrieving the account ID.
+func EnumerateS3(ctx context.Context, cfg aws.Config, regions []string, bucketNames []string, bucketPrefix string) methodaws.S3Report {
 	accountID, err := sts.GetAccountID(ctx, cfg)
 	if err != nil {
 		return methodaws.S3Report{
@@ -122,7 +256,7 @@ func EnumerateS3(ctx context.Context, cfg aws.Config, regions []string) methodaw
 	// regardless of whether the LocationConstraint is empty or not.
 
 	if len(regions) > 0 {
-		cfg.Region = "us-east-1"
+		cfg.Region = locationRegion
 	} else {
 		errorMsg := "No regions provided for S3 enumeration"
 		return methodaws.S3Report{
@@ -145,67 +279,74 @@ func EnumerateS3(ctx context.Context, cfg aws.Config, regions []string) methodaw
 	s3Buckets := []*methodaws.Bucket{}
 	errorMessages := []string{}
 
-	for _, bucket := range listBucketsOutput.Buckets {
-		s3Bucket := &methodaws.Bucket{
-			CreationDate: aws.ToTime(bucket.CreationDate),
-			Name:         aws.ToString(bucket.Name),
-			OwnerId:      aws.ToString(listBucketsOutput.Owner.ID),
-			OwnerName:    aws.ToString(listBucketsOutput.Owner.DisplayName),
-		}
-
-		// Get the bucket's region
-		regionOutput, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: bucket.Name})
+	// The account-level Public Access Block overrides the settings of every bucket
+	accountPublicAccessConfig, err := accountPublicAccess(ctx, cfg, aws.ToString(accountID))
+	if err != nil {
+		errorMessages = append(errorMessages, err.Error())
+	}
 
-		if err != nil {
-			errorMessages = append(errorMessages, fmt.Sprintf("Error getting location for bucket %s: %v", *bucket.Name, err))
-			continue
-		}
-		s3Bucket.Region = string(regionOutput.LocationConstraint)
-		if s3Bucket.Region == "" {
-			s3Bucket.Region = "us-east-1"
+	buckets := []types.Bucket{}
+	found := map[string]bool{}
+	for _, bucket := range listBucketsOutput.Buckets {
+		if matchesBucketFilter(aws.ToString(bucket.Name), bucketNames, bucketPrefix) {
+			buckets = append(buckets, bucket)
+			found[aws.ToString(bucket.Name)] = true
 		}
-
-		// Create a new client for the bucket's specific region
-		bucketCfg := cfg.Copy()
-		bucketCfg.Region = s3Bucket.Region
-		bucketClient := s3.NewFromConfig(bucketCfg)
-
-		// Fetch additional bucket details
-		s3Bucket, err = bucketPolicy(ctx, bucketClient, s3Bucket)
-		if err != nil {
-			errorMessages = append(errorMessages, err.Error())
+	}
+	for _, name := range bucketNames {
+		if !found[name] {
+			errorMessages = append(errorMessages, fmt.Sprintf("Bucket %s not found", name))
 		}
+	}
 
-		s3Bucket, err = objectVersioning(ctx, bucketClient, s3Bucket)
-		if err != nil {
-			errorMessages = append(errorMessages, err.Error())
-		}
+	// Enrich the buckets concurrently, keeping the results and errors in the order the buckets were listed
+	clients := newClientCache(cfg)
+	results := make([]*methodaws.Bucket, len(buckets))
+	resultErrors := make([][]string, len(buckets))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < bucketWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], resultErrors[i] = enrichBucket(ctx, clients, buckets[i], listBucketsOutput.Owner, aws.ToString(accountID), accountPublicAccessConfig)
+			}
+		}()
+	}
+	for i := range buckets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
-		s3Bucket, err = bucketEncryption(ctx, bucketClient, s3Bucket)
-		if err != nil {
-			errorMessages = append(errorMessages, err.Error())
+	for i, s3Bucket := range results {
+		errorMessages = append(errorMessages, resultErrors[i]...)
+		if s3Bucket != nil {
+			s3Buckets = append(s3Buckets, s3Bucket)
 		}
+	}
 
-		s3Bucket, err = publicAccess(ctx, bucketClient, s3Bucket)
+	accessPoints := []*methodaws.S3AccessPoint{}
+	for _, region := range regions {
+		regionAccessPoints, err := listAccessPoints(ctx, cfg, aws.ToString(accountID), region)
 		if err != nil {
-			errorMessages = append(errorMessages, err.Error())
+			errorMessages = append(errorMessages, fmt.Sprintf("Error in region %s: %v", region, err))
 		}
+		accessPoints = append(accessPoints, regionAccessPoints...)
+	}
 
-		s3Bucket.Url = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", *bucket.Name, s3Bucket.Region)
-
-		bucketARN := arn.ARN{
-			Partition: "aws",
-			Service:   "s3",
-			Resource:  *bucket.Name,
-		}
-		s3Bucket.Arn = bucketARN.String()
-
-		s3Buckets = append(s3Buckets, s3Bucket)
+	multiRegionAccessPoints, err := listMultiRegionAccessPoints(ctx, cfg, aws.ToString(accountID))
+	if err != nil {
+		errorMessages = append(errorMessages, err.Error())
 	}
 
 	return methodaws.S3Report{
-		AccountId: aws.ToString(accountID),
-		S3Buckets: s3Buckets,
-		Errors:    errorMessages,
+		AccountId:                 aws.ToString(accountID),
+		AccountPublicAccessConfig: accountPublicAccessConfig,
+		S3Buckets:                 s3Buckets,
+		AccessPoints:              accessPoints,
+		MultiRegionAccessPoints:   multiRegionAccessPoints,
+		Errors:                    errorMessages,
 	}
 }
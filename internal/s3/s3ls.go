@@ -2,21 +2,62 @@ package s3
 
 import (
 	"context"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
-// BucketObject contains the name and size (in bytes) of an object stored in an S3 bucket.
+// maxKeysPerPage is the largest page S3 returns from a single list request.
+const maxKeysPerPage = 1000
+
+// LsOptions limits and shapes the listing performed by LsS3Bucket. A zero MaxKeys lists every key. When StartAfter is
+// set, listing starts after that key.
+type LsOptions struct {
+	Prefix          string
+	Delimiter       string
+	StartAfter      string
+	MaxKeys         int
+	IncludeVersions bool
+	IncludeMetadata bool
+}
+
+// BucketObject contains the name and size (in bytes) of an object stored in an S3 bucket. Version fields are set when
+// versions are listed, and the storage class, ETag, last-modified time and owner are set when metadata is requested.
 type BucketObject struct {
-	Name string `json:"name" yaml:"name"`
-	Size int64  `json:"size" yaml:"size"`
+	Name         string     `json:"name" yaml:"name"`
+	Size         int64      `json:"size" yaml:"size"`
+	VersionID    *string    `json:"version_id,omitempty" yaml:"version_id,omitempty"`
+	IsLatest     *bool      `json:"is_latest,omitempty" yaml:"is_latest,omitempty"`
+	DeleteMarker bool       `json:"delete_marker,omitempty" yaml:"delete_marker,omitempty"`
+	StorageClass *string    `json:"storage_class,omitempty" yaml:"storage_class,omitempty"`
+	ETag         *string    `json:"etag,omitempty" yaml:"etag,omitempty"`
+	LastModified *time.Time `json:"last_modified,omitempty" yaml:"last_modified,omitempty"`
+	OwnerID      *string    `json:"owner_id,omitempty" yaml:"owner_id,omitempty"`
+	OwnerName    *string    `json:"owner_name,omitempty" yaml:"owner_name,omitempty"`
+}
+
+// PrefixSummary totals the objects listed under a single prefix. Delete markers are not counted.
+type PrefixSummary struct {
+	Prefix      string `json:"prefix" yaml:"prefix"`
+	ObjectCount int    `json:"object_count" yaml:"object_count"`
+	TotalSize   int64  `json:"total_size" yaml:"total_size"`
 }
 
-// LsResources contains the S3 bucket name and the objects stored in the bucket.
+// LsResources contains the S3 bucket name and the objects stored in the bucket. When a delimiter is given, the keys
+// that share a prefix up to the delimiter are rolled up into CommonPrefixes, folder-style. Truncated is set when the
+// listing stopped at the maximum number of keys.
 type LsResources struct {
-	S3BucketName  *string        `json:"name" yaml:"name"`
-	BucketObjects []BucketObject `json:"objects" yaml:"objects"`
+	S3BucketName   *string         `json:"name" yaml:"name"`
+	Prefix         *string         `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Delimiter      *string         `json:"delimiter,omitempty" yaml:"delimiter,omitempty"`
+	BucketObjects  []BucketObject  `json:"objects" yaml:"objects"`
+	CommonPrefixes []string        `json:"common_prefixes,omitempty" yaml:"common_prefixes,omitempty"`
+	Summaries      []PrefixSummary `json:"summaries" yaml:"summaries"`
+	Truncated      bool            `json:"truncated" yaml:"truncated"`
 }
 
 // LsResourceReport contains the resources discovered in an S3 bucket and any non-fatal errors that occurred during the
@@ -26,40 +67,227 @@ type LsResourceReport struct {
 	Errors    []string    `json:"errors" yaml:"errors"`
 }
 
-// LsS3Bucket retrieves the objects stored in an S3 bucket and returns an LsResourceReport struct
-func LsS3Bucket(ctx context.Context, cfg aws.Config, bucketName string) (*LsResourceReport, error) {
+// LsS3Bucket retrieves the objects stored in an S3 bucket and returns an LsResourceReport struct. Object versions and
+// delete markers are listed in place of objects when options.IncludeVersions is set.
+func LsS3Bucket(ctx context.Context, cfg aws.Config, bucketName string, options LsOptions) (*LsResourceReport, error) {
 	s3Client := s3.NewFromConfig(cfg)
 	errors := []string{}
 
+	resources := LsResources{
+		S3BucketName:   aws.String(bucketName),
+		BucketObjects:  []BucketObject{},
+		CommonPrefixes: []string{},
+	}
+	if options.Prefix != "" {
+		resources.Prefix = aws.String(options.Prefix)
+	}
+	if options.Delimiter != "" {
+		resources.Delimiter = aws.String(options.Delimiter)
+	}
+
+	var err error
+	if options.IncludeVersions {
+		err = listObjectVersions(ctx, s3Client, bucketName, options, &resources)
+	} else {
+		err = listObjects(ctx, s3Client, bucketName, options, &resources)
+	}
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	resources.Summaries = summarizePrefixes(resources.BucketObjects, options)
+
+	report := LsResourceReport{
+		Resources: resources,
+		Errors:    errors,
+	}
+
+	return &report, nil
+}
+
+// listObjects lists the current objects in a bucket, stopping once the maximum number of keys has been listed.
+func listObjects(ctx context.Context, s3Client *s3.Client, bucketName string, options LsOptions, resources *LsResources) error {
 	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(bucketName),
+		Bucket:     aws.String(bucketName),
+		FetchOwner: aws.Bool(options.IncludeMetadata),
+	}
+	if options.Prefix != "" {
+		input.Prefix = aws.String(options.Prefix)
+	}
+	if options.Delimiter != "" {
+		input.Delimiter = aws.String(options.Delimiter)
+	}
+	if options.StartAfter != "" {
+		input.StartAfter = aws.String(options.StartAfter)
 	}
 
-	var bucketObjects []BucketObject
+	for {
+		remaining, ok := remainingKeys(options, resources)
+		if !ok {
+			resources.Truncated = true
+			return nil
+		}
+		input.MaxKeys = aws.Int32(remaining)
 
-	paginator := s3.NewListObjectsV2Paginator(s3Client, input)
-	for paginator.HasMorePages() {
-		output, err := paginator.NextPage(context.Background())
+		output, err := s3Client.ListObjectsV2(ctx, input)
 		if err != nil {
-			errors = append(errors, err.Error())
+			return err
 		}
 		for _, item := range output.Contents {
-			bucketObjects = append(bucketObjects, BucketObject{
-				Name: *item.Key,
-				Size: *item.Size,
-			})
+			object := BucketObject{
+				Name: aws.ToString(item.Key),
+				Size: aws.ToInt64(item.Size),
+			}
+			if options.IncludeMetadata {
+				object.StorageClass = storageClass(string(item.StorageClass))
+				object.ETag = item.ETag
+				object.LastModified = item.LastModified
+				object.OwnerID, object.OwnerName = owner(item.Owner)
+			}
+			resources.BucketObjects = append(resources.BucketObjects, object)
 		}
+		for _, commonPrefix := range output.CommonPrefixes {
+			resources.CommonPrefixes = append(resources.CommonPrefixes, aws.ToString(commonPrefix.Prefix))
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			return nil
+		}
+		input.ContinuationToken = output.NextContinuationToken
 	}
+}
 
-	resources := LsResources{
-		S3BucketName:  aws.String(bucketName),
-		BucketObjects: bucketObjects,
+// listObjectVersions lists every version and delete marker in a bucket, stopping once the maximum number of keys has
+// been listed. Versions and delete markers are returned by S3 in separate lists and are merged by key.
+func listObjectVersions(ctx context.Context, s3Client *s3.Client, bucketName string, options LsOptions, resources *LsResources) error {
+	input := &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucketName),
+	}
+	if options.Prefix != "" {
+		input.Prefix = aws.String(options.Prefix)
+	}
+	if options.Delimiter != "" {
+		input.Delimiter = aws.String(options.Delimiter)
+	}
+	if options.StartAfter != "" {
+		input.KeyMarker = aws.String(options.StartAfter)
 	}
 
-	report := LsResourceReport{
-		Resources: resources,
-		Errors:    errors,
+	for {
+		remaining, ok := remainingKeys(options, resources)
+		if !ok {
+			resources.Truncated = true
+			return nil
+		}
+		input.MaxKeys = aws.Int32(remaining)
+
+		output, err := s3Client.ListObjectVersions(ctx, input)
+		if err != nil {
+			return err
+		}
+		page := []BucketObject{}
+		for _, version := range output.Versions {
+			object := BucketObject{
+				Name:      aws.ToString(version.Key),
+				Size:      aws.ToInt64(version.Size),
+				VersionID: version.VersionId,
+				IsLatest:  version.IsLatest,
+			}
+			if options.IncludeMetadata {
+				object.StorageClass = storageClass(string(version.StorageClass))
+				object.ETag = version.ETag
+				object.LastModified = version.LastModified
+				object.OwnerID, object.OwnerName = owner(version.Owner)
+			}
+			page = append(page, object)
+		}
+		for _, marker := range output.DeleteMarkers {
+			object := BucketObject{
+				Name:         aws.ToString(marker.Key),
+				VersionID:    marker.VersionId,
+				IsLatest:     marker.IsLatest,
+				DeleteMarker: true,
+			}
+			if options.IncludeMetadata {
+				object.LastModified = marker.LastModified
+				object.OwnerID, object.OwnerName = owner(marker.Owner)
+			}
+			page = append(page, object)
+		}
+		sort.SliceStable(page, func(i, j int) bool { return page[i].Name < page[j].Name })
+		resources.BucketObjects = append(resources.BucketObjects, page...)
+		for _, commonPrefix := range output.CommonPrefixes {
+			resources.CommonPrefixes = append(resources.CommonPrefixes, aws.ToString(commonPrefix.Prefix))
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			return nil
+		}
+		input.KeyMarker = output.NextKeyMarker
+		input.VersionIdMarker = output.NextVersionIdMarker
 	}
+}
 
-	return &report, nil
+// remainingKeys returns the page size for the next list request. It returns false once the maximum number of keys,
+// counting both objects and common prefixes, has been listed.
+func remainingKeys(options LsOptions, resources *LsResources) (int32, bool) {
+	if options.MaxKeys <= 0 {
+		return maxKeysPerPage, true
+	}
+	remaining := options.MaxKeys - len(resources.BucketObjects) - len(resources.CommonPrefixes)
+	if remaining <= 0 {
+		return 0, false
+	}
+	if remaining > maxKeysPerPage {
+		remaining = maxKeysPerPage
+	}
+	return int32(remaining), true
+}
+
+// summarizePrefixes totals the listed objects by the prefix they sit under, which is the key up to and including its
+// last delimiter ("/" when no delimiter is given). Objects without a delimiter in their key are totalled under the
+// listing prefix. Summaries are sorted by prefix.
+func summarizePrefixes(objects []BucketObject, options LsOptions) []PrefixSummary {
+	delimiter := options.Delimiter
+	if delimiter == "" {
+		delimiter = "/"
+	}
+
+	totals := map[string]*PrefixSummary{}
+	for _, object := range objects {
+		if object.DeleteMarker {
+			continue
+		}
+		prefix := options.Prefix
+		if i := strings.LastIndex(object.Name, delimiter); i >= len(options.Prefix) {
+			prefix = object.Name[:i+len(delimiter)]
+		}
+		summary, ok := totals[prefix]
+		if !ok {
+			summary = &PrefixSummary{Prefix: prefix}
+			totals[prefix] = summary
+		}
+		summary.ObjectCount++
+		summary.TotalSize += object.Size
+	}
+
+	summaries := []PrefixSummary{}
+	for _, summary := range totals {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Prefix < summaries[j].Prefix })
+	return summaries
+}
+
+func storageClass(class string) *string {
+	if class == "" {
+		return nil
+	}
+	return aws.String(class)
+}
+
+func owner(objectOwner *types.Owner) (*string, *string) {
+	if objectOwner == nil {
+		return nil, nil
+	}
+	return objectOwner.ID, objectOwner.DisplayName
 }
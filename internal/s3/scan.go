@@ -0,0 +1,282 @@
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Method-Security/methodaws/internal/common"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// maxScanLineSize is the longest line the scanner reads from an object. Longer lines stop the scan of that object.
+	maxScanLineSize = 1024 * 1024
+
+	// maxFindingsPerObject caps the findings reported for a single object so that one large file cannot flood the report.
+	maxFindingsPerObject = 50
+
+	// binarySniffSize is the number of leading bytes checked for a NUL byte to decide whether an object is binary.
+	binarySniffSize = 512
+)
+
+// ScanOptions controls which objects of a bucket are sampled. The first SampleSize non-empty objects listed are scanned,
+// reading at most MaxObjectSize bytes from each. When KeyPatterns is set, only keys matching one of the patterns are
+// considered; the patterns may contain the * and ? wildcards.
+type ScanOptions struct {
+	Prefix        string
+	SampleSize    int
+	MaxObjectSize int64
+	KeyPatterns   []string
+}
+
+// ScanFinding is a single match of a detector. The match is redacted unless the detector matches a non-secret marker,
+// such as the header of a private key. Line is zero for findings based on the object's key.
+type ScanFinding struct {
+	Detector string `json:"detector" yaml:"detector"`
+	Line     int    `json:"line,omitempty" yaml:"line,omitempty"`
+	Match    string `json:"match" yaml:"match"`
+}
+
+// ScannedObject contains the findings for a single object. Truncated is set when only the first bytes of an object
+// larger than the maximum object size were scanned, or when its findings were cut off at maxFindingsPerObject.
+type ScannedObject struct {
+	Key       string        `json:"key" yaml:"key"`
+	Size      int64         `json:"size" yaml:"size"`
+	Scanned   bool          `json:"scanned" yaml:"scanned"`
+	Truncated bool          `json:"truncated" yaml:"truncated"`
+	Findings  []ScanFinding `json:"findings" yaml:"findings"`
+}
+
+// ScanResources contains the S3 bucket name, how many objects matching the key patterns were listed before the sample
+// was collected, how many of them were scanned, and the objects with findings.
+type ScanResources struct {
+	S3BucketName   *string         `json:"name" yaml:"name"`
+	ObjectsListed  int             `json:"objects_listed" yaml:"objects_listed"`
+	ObjectsScanned int             `json:"objects_scanned" yaml:"objects_scanned"`
+	Objects        []ScannedObject `json:"objects" yaml:"objects"`
+}
+
+// ScanResourceReport contains the findings in an S3 bucket and any non-fatal errors that occurred during the execution
+// of the `methodaws s3 scan` subcommand.
+type ScanResourceReport struct {
+	Resources ScanResources `json:"resources" yaml:"resources"`
+	Errors    []string      `json:"errors" yaml:"errors"`
+}
+
+// contentDetector matches sensitive data in a single line of an object. The optional validate function rejects
+// matches that fit the pattern but are not real, such as numbers that fail the credit card checksum.
+type contentDetector struct {
+	name      string
+	pattern   *regexp.Regexp
+	validate  func(match string) bool
+	sensitive bool
+}
+
+var contentDetectors = []contentDetector{
+	{name: "aws_access_key_id", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), sensitive: true},
+	{name: "aws_secret_access_key", pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}`), sensitive: true},
+	{name: "private_key", pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY( BLOCK)?-----`)},
+	{name: "credential_assignment", pattern: regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|secret|api_?key|access_?token|auth_?token)["']?\s*[:=]\s*["']?[^\s"']{8,}`), sensitive: true},
+	{name: "email_address", pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`), sensitive: true},
+	{name: "credit_card_number", pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), validate: luhnValid, sensitive: true},
+}
+
+// configFilePatterns are object names, matched against the last path segment of a key, that commonly hold secrets.
+var configFilePatterns = []string{
+	".env", ".env.*", "*.env",
+	"*.pem", "*.key", "*.ppk", "*.p12", "*.pfx", "*.jks", "*.kdbx",
+	"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519",
+	".npmrc", ".pypirc", ".netrc", ".git-credentials", ".htpasswd", ".pgpass", ".dockercfg",
+	"credentials", "credentials.json", "secrets.*", "*.tfstate", "*.tfstate.backup", "*.tfvars",
+	"wp-config.php", "settings.py", "application.properties", "application.yml", "web.config",
+}
+
+// ScanS3Bucket samples the objects in an S3 bucket and streams their content through the sensitive-data detectors. The
+// bucket is listed with LsS3Bucket one page at a time until SampleSize non-empty objects matching the key patterns have
+// been sampled, so that large buckets are not listed in full. Every listed key matching the key patterns is checked
+// against the known configuration file names, and the first MaxObjectSize bytes of each sampled object are scanned.
+// Binary objects are skipped.
+func ScanS3Bucket(ctx context.Context, cfg aws.Config, bucketName string, options ScanOptions) (*ScanResourceReport, error) {
+	if options.SampleSize <= 0 {
+		return nil, fmt.Errorf("invalid sample size %d, expected a positive number of objects", options.SampleSize)
+	}
+	if options.MaxObjectSize <= 0 {
+		return nil, fmt.Errorf("invalid maximum object size %d, expected a positive number of bytes", options.MaxObjectSize)
+	}
+	s3Client := s3.NewFromConfig(cfg)
+	errors := []string{}
+
+	resources := ScanResources{
+		S3BucketName: aws.String(bucketName),
+		Objects:      []ScannedObject{},
+	}
+	lsOptions := LsOptions{
+		Prefix:  options.Prefix,
+		MaxKeys: maxKeysPerPage,
+	}
+
+	sampled := 0
+	for sampled < options.SampleSize {
+		listing, err := LsS3Bucket(ctx, cfg, bucketName, lsOptions)
+		if err != nil {
+			errors = append(errors, err.Error())
+			break
+		}
+		errors = append(errors, listing.Errors...)
+
+		for _, object := range listing.Resources.BucketObjects {
+			if sampled == options.SampleSize {
+				break
+			}
+			if !matchesKeyPatterns(object.Name, options.KeyPatterns) {
+				continue
+			}
+			resources.ObjectsListed++
+
+			scannedObject := ScannedObject{
+				Key:      object.Name,
+				Size:     object.Size,
+				Findings: keyFindings(object.Name),
+			}
+			if object.Size > 0 {
+				sampled++
+				findings, truncated, scanned, err := scanObject(ctx, s3Client, bucketName, object.Name, options.MaxObjectSize)
+				if err != nil {
+					errors = append(errors, fmt.Sprintf("Error scanning object %s: %v", object.Name, err))
+				}
+				if scanned {
+					resources.ObjectsScanned++
+				}
+				scannedObject.Scanned = scanned
+				scannedObject.Truncated = scanned && (truncated || object.Size > options.MaxObjectSize)
+				scannedObject.Findings = append(scannedObject.Findings, findings...)
+			}
+
+			if len(scannedObject.Findings) > 0 {
+				resources.Objects = append(resources.Objects, scannedObject)
+			}
+		}
+
+		objects := listing.Resources.BucketObjects
+		if !listing.Resources.Truncated || len(listing.Errors) > 0 || len(objects) == 0 {
+			break
+		}
+		lsOptions.StartAfter = objects[len(objects)-1].Name
+	}
+
+	report := ScanResourceReport{
+		Resources: resources,
+		Errors:    errors,
+	}
+
+	return &report, nil
+}
+
+// matchesKeyPatterns reports whether a key matches one of the patterns. An empty list matches every key.
+func matchesKeyPatterns(key string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if common.WildcardMatch(pattern, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyFindings reports an object whose name matches a known configuration file name.
+func keyFindings(key string) []ScanFinding {
+	name := strings.ToLower(path.Base(key))
+	for _, pattern := range configFilePatterns {
+		if common.WildcardMatch(pattern, name) {
+			return []ScanFinding{{Detector: "config_file", Match: key}}
+		}
+	}
+	return []ScanFinding{}
+}
+
+// scanObject streams up to maxSize bytes of an object through the content detectors line by line. It reports whether
+// the findings were truncated at maxFindingsPerObject and whether the object was scanned at all; binary objects are not.
+func scanObject(ctx context.Context, s3Client *s3.Client, bucketName string, key string, maxSize int64) ([]ScanFinding, bool, bool, error) {
+	output, err := s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", maxSize-1)),
+	})
+	if err != nil {
+		return nil, false, false, err
+	}
+	defer output.Body.Close()
+
+	reader := bufio.NewReader(io.LimitReader(output.Body, maxSize))
+	head, err := reader.Peek(binarySniffSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, false, false, err
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, false, false, nil
+	}
+
+	findings := []ScanFinding{}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxScanLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		for _, detector := range contentDetectors {
+			for _, match := range detector.pattern.FindAllString(scanner.Text(), -1) {
+				if detector.validate != nil && !detector.validate(match) {
+					continue
+				}
+				if len(findings) == maxFindingsPerObject {
+					return findings, true, true, nil
+				}
+				if detector.sensitive {
+					match = redact(match)
+				}
+				findings = append(findings, ScanFinding{Detector: detector.name, Line: line, Match: match})
+			}
+		}
+	}
+	return findings, false, true, scanner.Err()
+}
+
+// redact masks a match, keeping its first four and last two characters when it is long enough that they do not give
+// the value away. Characters are counted as runes so that multi-byte characters are never split.
+func redact(match string) string {
+	runes := []rune(match)
+	if len(runes) <= 12 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:4]) + strings.Repeat("*", len(runes)-6) + string(runes[len(runes)-2:])
+}
+
+// luhnValid reports whether a candidate card number, ignoring spaces and dashes, passes the Luhn checksum.
+func luhnValid(candidate string) bool {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(candidate)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
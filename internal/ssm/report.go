@@ -0,0 +1,51 @@
+// Package ssm provides the data structures and logic necessary to enumerate AWS Systems Manager resources and
+// determine which EC2 instances are reachable through it.
+package ssm
+
+import (
+	"github.com/Method-Security/methodaws/internal/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ManagedInstance represents a node that is registered with Systems Manager. When the node is an EC2 instance that
+// the caller can see, the instance and its IAM roles are attached so that reachability can be tied back to the
+// permissions that a session on the node would inherit.
+type ManagedInstance struct {
+	Instance    types.InstanceInformation `json:"instance" yaml:"instance"`
+	Reachable   bool                      `json:"reachable" yaml:"reachable"`
+	IAMRoles    []string                  `json:"iam_roles,omitempty" yaml:"iam_roles,omitempty"`
+	EC2Instance *ec2.InstanceWithIAMRole  `json:"ec2_instance,omitempty" yaml:"ec2_instance,omitempty"`
+	Region      string                    `json:"region" yaml:"region"`
+}
+
+// SharedDocument represents an SSM document owned by the account that has been shared with other accounts. Public is
+// true when the document is shared with all AWS accounts.
+type SharedDocument struct {
+	Document         types.DocumentIdentifier `json:"document" yaml:"document"`
+	Public           bool                     `json:"public" yaml:"public"`
+	SharedAccountIDs []string                 `json:"shared_account_ids" yaml:"shared_account_ids"`
+	Region           string                   `json:"region" yaml:"region"`
+}
+
+// Parameter represents the metadata of an SSM Parameter Store parameter. Parameter values are never retrieved.
+type Parameter struct {
+	Parameter types.ParameterMetadata `json:"parameter" yaml:"parameter"`
+	Region    string                  `json:"region" yaml:"region"`
+}
+
+// Resources contains the Systems Manager resources that were enumerated. UnmanagedEC2InstanceIDs lists the EC2
+// instances that are not registered with Systems Manager and are therefore not reachable through it.
+type Resources struct {
+	ManagedInstances        []ManagedInstance `json:"managed_instances" yaml:"managed_instances"`
+	UnmanagedEC2InstanceIDs []string          `json:"unmanaged_ec2_instance_ids" yaml:"unmanaged_ec2_instance_ids"`
+	SharedDocuments         []SharedDocument  `json:"shared_documents" yaml:"shared_documents"`
+	Parameters              []Parameter       `json:"parameters" yaml:"parameters"`
+}
+
+// Report contains the account ID that the resources were discovered in, the resources themselves, and any
+// non-fatal errors that occurred during the execution of the `methodaws ssm enumerate` subcommand.
+type Report struct {
+	AccountID string    `json:"account_id" yaml:"account_id"`
+	Resources Resources `json:"resources" yaml:"resources"`
+	Errors    []string  `json:"errors" yaml:"errors"`
+}
@@ -0,0 +1,233 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Method-Security/methodaws/internal/ec2"
+	"github.com/Method-Security/methodaws/internal/sts"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// EnumerateSSMForRegion lists the Systems Manager managed instances, shared documents and parameters for a
+// particular region. Managed instances are cross-referenced with the EC2 instances in the region so that each
+// reachable instance carries its IAM roles, and EC2 instances that are not managed are reported separately. Unmanaged
+// instances are only reported when every managed instance could be listed.
+func EnumerateSSMForRegion(ctx context.Context, cfg aws.Config, region string) (*Report, error) {
+	cfg.Region = region
+
+	svc := ssm.NewFromConfig(cfg)
+	resources := Resources{
+		ManagedInstances:        []ManagedInstance{},
+		UnmanagedEC2InstanceIDs: []string{},
+		SharedDocuments:         []SharedDocument{},
+		Parameters:              []Parameter{},
+	}
+	errors := []string{}
+
+	accountID, err := sts.GetAccountID(ctx, cfg)
+	if err != nil {
+		errors = append(errors, err.Error())
+		return &Report{Resources: resources, Errors: errors}, err
+	}
+
+	// Index the EC2 instances in the region so they can be matched against managed instances
+	ec2Instances := map[string]ec2.InstanceWithIAMRole{}
+	ec2Report, err := ec2.EnumerateEc2ForRegion(ctx, cfg, region)
+	if err != nil {
+		errors = append(errors, err.Error())
+	} else {
+		for _, instance := range ec2Report.Resources.EC2Instances {
+			ec2Instances[aws.ToString(instance.Instance.InstanceId)] = instance
+		}
+		errors = append(errors, ec2Report.Errors...)
+	}
+
+	managed := map[string]bool{}
+	managedComplete := true
+	paginator := ssm.NewDescribeInstanceInformationPaginator(svc, &ssm.DescribeInstanceInformationInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errors = append(errors, err.Error())
+			managedComplete = false
+			break
+		}
+
+		for _, info := range page.InstanceInformationList {
+			instanceID := aws.ToString(info.InstanceId)
+			managedInstance := ManagedInstance{
+				Instance:  info,
+				Reachable: info.PingStatus == types.PingStatusOnline,
+				IAMRoles:  []string{},
+				Region:    region,
+			}
+
+			if ec2Instance, ok := ec2Instances[instanceID]; ok {
+				managedInstance.EC2Instance = &ec2Instance
+				managedInstance.IAMRoles = append(managedInstance.IAMRoles, ec2Instance.IAMRoles...)
+			} else if info.IamRole != nil {
+				// Hybrid nodes report the service role they were activated with rather than an instance profile
+				managedInstance.IAMRoles = append(managedInstance.IAMRoles, *info.IamRole)
+			}
+
+			managed[instanceID] = true
+			resources.ManagedInstances = append(resources.ManagedInstances, managedInstance)
+		}
+	}
+
+	// Without the full list of managed instances every missing instance would appear unmanaged, so skip the comparison
+	if managedComplete {
+		for instanceID := range ec2Instances {
+			if !managed[instanceID] {
+				resources.UnmanagedEC2InstanceIDs = append(resources.UnmanagedEC2InstanceIDs, instanceID)
+			}
+		}
+		sort.Strings(resources.UnmanagedEC2InstanceIDs)
+	}
+
+	documents, documentErrors := listSharedDocuments(ctx, svc, region)
+	errors = append(errors, documentErrors...)
+	resources.SharedDocuments = append(resources.SharedDocuments, documents...)
+
+	parameters, err := listParameters(ctx, svc, region)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	resources.Parameters = append(resources.Parameters, parameters...)
+
+	return &Report{
+		AccountID: aws.ToString(accountID),
+		Resources: resources,
+		Errors:    errors,
+	}, nil
+}
+
+// EnumerateSSM lists the Systems Manager resources for each of the specified regions and consolidates the individual
+// region reports into a single report.
+func EnumerateSSM(ctx context.Context, cfg aws.Config, regions []string) (*Report, error) {
+	accountID, err := sts.GetAccountID(ctx, cfg)
+	if err != nil {
+		return &Report{Errors: []string{err.Error()}}, err
+	}
+
+	report := Report{
+		AccountID: aws.ToString(accountID),
+		Resources: Resources{
+			ManagedInstances:        []ManagedInstance{},
+			UnmanagedEC2InstanceIDs: []string{},
+			SharedDocuments:         []SharedDocument{},
+			Parameters:              []Parameter{},
+		},
+		Errors: []string{},
+	}
+
+	for _, region := range regions {
+		r, err := EnumerateSSMForRegion(ctx, cfg, region)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("Error in region %s: %s", region, err.Error()))
+			continue
+		}
+		report.Resources.ManagedInstances = append(report.Resources.ManagedInstances, r.Resources.ManagedInstances...)
+		report.Resources.UnmanagedEC2InstanceIDs = append(report.Resources.UnmanagedEC2InstanceIDs, r.Resources.UnmanagedEC2InstanceIDs...)
+		report.Resources.SharedDocuments = append(report.Resources.SharedDocuments, r.Resources.SharedDocuments...)
+		report.Resources.Parameters = append(report.Resources.Parameters, r.Resources.Parameters...)
+		for _, e := range r.Errors {
+			report.Errors = append(report.Errors, fmt.Sprintf("Error in region %s: %s", region, e))
+		}
+	}
+
+	return &report, nil
+}
+
+// listSharedDocuments returns the documents owned by the account that have been shared with at least one other
+// account. A document shared with "all" is public. Documents whose permissions cannot be described are recorded as
+// non-fatal errors and skipped.
+func listSharedDocuments(ctx context.Context, svc *ssm.Client, region string) ([]SharedDocument, []string) {
+	documents := []SharedDocument{}
+	errors := []string{}
+	paginator := ssm.NewListDocumentsPaginator(svc, &ssm.ListDocumentsInput{
+		Filters: []types.DocumentKeyValuesFilter{
+			{Key: aws.String("Owner"), Values: []string{"Self"}},
+		},
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errors = append(errors, err.Error())
+			break
+		}
+
+		for _, document := range page.DocumentIdentifiers {
+			accountIDs, err := getDocumentSharedAccounts(ctx, svc, aws.ToString(document.Name))
+			if err != nil {
+				errors = append(errors, err.Error())
+				continue
+			}
+			if len(accountIDs) == 0 {
+				continue
+			}
+
+			sharedDocument := SharedDocument{
+				Document:         document,
+				SharedAccountIDs: accountIDs,
+				Region:           region,
+			}
+			for _, accountID := range accountIDs {
+				if accountID == "all" {
+					sharedDocument.Public = true
+				}
+			}
+			documents = append(documents, sharedDocument)
+		}
+	}
+
+	return documents, errors
+}
+
+// getDocumentSharedAccounts returns the account IDs that a document has been shared with.
+func getDocumentSharedAccounts(ctx context.Context, svc *ssm.Client, name string) ([]string, error) {
+	accountIDs := []string{}
+	input := &ssm.DescribeDocumentPermissionInput{
+		Name:           aws.String(name),
+		PermissionType: types.DocumentPermissionTypeShare,
+	}
+
+	for {
+		output, err := svc.DescribeDocumentPermission(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("error describing permissions for document %s: %v", name, err)
+		}
+		accountIDs = append(accountIDs, output.AccountIds...)
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	return accountIDs, nil
+}
+
+// listParameters returns the metadata for every parameter in Parameter Store. DescribeParameters never returns
+// parameter values.
+func listParameters(ctx context.Context, svc *ssm.Client, region string) ([]Parameter, error) {
+	parameters := []Parameter{}
+	paginator := ssm.NewDescribeParametersPaginator(svc, &ssm.DescribeParametersInput{})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return parameters, err
+		}
+
+		for _, parameter := range page.Parameters {
+			parameters = append(parameters, Parameter{Parameter: parameter, Region: region})
+		}
+	}
+
+	return parameters, nil
+}
@@ -0,0 +1,389 @@
+package vpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/directconnect"
+	dctypes "github.com/aws/aws-sdk-go-v2/service/directconnect/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+const (
+	hybridTypeVPN           = "vpn"
+	hybridTypeDirectConnect = "direct_connect"
+
+	gatewayTypeVirtualPrivateGateway = "virtual_private_gateway"
+	gatewayTypeTransitGateway        = "transit_gateway"
+
+	// maxTransitGatewayRoutes is the largest number of routes SearchTransitGatewayRoutes returns in a single call
+	maxTransitGatewayRoutes = 1000
+)
+
+// newHybridConnectivity returns an empty HybridConnectivity so that every list is serialized as an empty array rather
+// than null.
+func newHybridConnectivity() HybridConnectivity {
+	return HybridConnectivity{
+		TransitGateways:                  []TransitGateway{},
+		VPNGateways:                      []types.VpnGateway{},
+		VPNConnections:                   []VPNConnection{},
+		CustomerGateways:                 []types.CustomerGateway{},
+		VirtualInterfaces:                []dctypes.VirtualInterface{},
+		DirectConnectGatewayAssociations: []dctypes.DirectConnectGatewayAssociation{},
+	}
+}
+
+// enumerateHybridConnectivityForRegion describes the transit gateways, VPN gateways, site-to-site VPN connections,
+// customer gateways and Direct Connect virtual interfaces of a region. Direct Connect gateways are global, so their
+// associations may reference gateways in other regions. A failure to describe one resource type is recorded as a
+// non-fatal error and the rest of the resources are still returned.
+func enumerateHybridConnectivityForRegion(ctx context.Context, cfg aws.Config, region string) (HybridConnectivity, []string) {
+	cfg.Region = region
+	svc := ec2.NewFromConfig(cfg)
+	dcSvc := directconnect.NewFromConfig(cfg)
+	hybrid := newHybridConnectivity()
+	errors := []string{}
+
+	transitGateways, transitGatewayErrors := describeTransitGateways(ctx, svc, region)
+	errors = append(errors, transitGatewayErrors...)
+	hybrid.TransitGateways = append(hybrid.TransitGateways, transitGateways...)
+
+	vpnGateways, err := svc.DescribeVpnGateways(ctx, &ec2.DescribeVpnGatewaysInput{})
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("error describing VPN gateways: %v", err))
+	} else {
+		hybrid.VPNGateways = append(hybrid.VPNGateways, vpnGateways.VpnGateways...)
+	}
+
+	vpnConnections, err := svc.DescribeVpnConnections(ctx, &ec2.DescribeVpnConnectionsInput{})
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("error describing VPN connections: %v", err))
+	} else {
+		for _, connection := range vpnConnections.VpnConnections {
+			hybrid.VPNConnections = append(hybrid.VPNConnections, newVPNConnection(connection, region))
+		}
+	}
+
+	customerGateways, err := svc.DescribeCustomerGateways(ctx, &ec2.DescribeCustomerGatewaysInput{})
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("error describing customer gateways: %v", err))
+	} else {
+		hybrid.CustomerGateways = append(hybrid.CustomerGateways, customerGateways.CustomerGateways...)
+	}
+
+	virtualInterfaces, err := dcSvc.DescribeVirtualInterfaces(ctx, &directconnect.DescribeVirtualInterfacesInput{})
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("error describing Direct Connect virtual interfaces: %v", err))
+		return hybrid, errors
+	}
+
+	directConnectGatewayIDs := []string{}
+	seen := map[string]bool{}
+	for _, virtualInterface := range virtualInterfaces.VirtualInterfaces {
+		hybrid.VirtualInterfaces = append(hybrid.VirtualInterfaces, redactVirtualInterface(virtualInterface))
+		gatewayID := aws.ToString(virtualInterface.DirectConnectGatewayId)
+		if gatewayID != "" && !seen[gatewayID] {
+			seen[gatewayID] = true
+			directConnectGatewayIDs = append(directConnectGatewayIDs, gatewayID)
+		}
+	}
+
+	for _, gatewayID := range directConnectGatewayIDs {
+		associations, err := describeDirectConnectGatewayAssociations(ctx, dcSvc, gatewayID)
+		if err != nil {
+			errors = append(errors, err.Error())
+		}
+		hybrid.DirectConnectGatewayAssociations = append(hybrid.DirectConnectGatewayAssociations, associations...)
+	}
+
+	return hybrid, errors
+}
+
+// mergeHybridConnectivity appends the resources of other to hybrid. Direct Connect gateway associations are global and
+// are reported by every region with a virtual interface on the gateway, so they are de-duplicated by association ID.
+func mergeHybridConnectivity(hybrid HybridConnectivity, other HybridConnectivity) HybridConnectivity {
+	hybrid.TransitGateways = append(hybrid.TransitGateways, other.TransitGateways...)
+	hybrid.VPNGateways = append(hybrid.VPNGateways, other.VPNGateways...)
+	hybrid.VPNConnections = append(hybrid.VPNConnections, other.VPNConnections...)
+	hybrid.CustomerGateways = append(hybrid.CustomerGateways, other.CustomerGateways...)
+	hybrid.VirtualInterfaces = append(hybrid.VirtualInterfaces, other.VirtualInterfaces...)
+
+	existing := map[string]bool{}
+	for _, association := range hybrid.DirectConnectGatewayAssociations {
+		existing[aws.ToString(association.AssociationId)] = true
+	}
+	for _, association := range other.DirectConnectGatewayAssociations {
+		if !existing[aws.ToString(association.AssociationId)] {
+			existing[aws.ToString(association.AssociationId)] = true
+			hybrid.DirectConnectGatewayAssociations = append(hybrid.DirectConnectGatewayAssociations, association)
+		}
+	}
+	return hybrid
+}
+
+// linkHybridConnections sets the HybridConnections of each VPC. A VPN connection or virtual interface reaches a VPC
+// when it terminates on a virtual private gateway attached to the VPC, or on a transit gateway with an available
+// attachment to the VPC. Transit gateway route table associations and propagations are not evaluated.
+func linkHybridConnections(vpcs []Instance, hybrid HybridConnectivity) {
+	// gatewayVPCs maps each virtual private gateway and transit gateway ID to the VPCs it is attached to
+	gatewayVPCs := map[string]map[string]bool{}
+	attach := func(gatewayID string, vpcID string) {
+		if gatewayVPCs[gatewayID] == nil {
+			gatewayVPCs[gatewayID] = map[string]bool{}
+		}
+		gatewayVPCs[gatewayID][vpcID] = true
+	}
+	for _, vpnGateway := range hybrid.VPNGateways {
+		for _, attachment := range vpnGateway.VpcAttachments {
+			if attachment.State == types.AttachmentStatusAttached {
+				attach(aws.ToString(vpnGateway.VpnGatewayId), aws.ToString(attachment.VpcId))
+			}
+		}
+	}
+	for _, instance := range vpcs {
+		for _, attachment := range instance.Topology.TransitGatewayAttachments {
+			if attachment.State == types.TransitGatewayAttachmentStateAvailable {
+				attach(aws.ToString(attachment.TransitGatewayId), aws.ToString(instance.VPC.VpcId))
+			}
+		}
+	}
+
+	connections := []HybridConnection{}
+	for _, vpn := range hybrid.VPNConnections {
+		if vpn.Connection.State == types.VpnStateDeleted {
+			continue
+		}
+		connection := HybridConnection{Type: hybridTypeVPN, ConnectionID: aws.ToString(vpn.Connection.VpnConnectionId)}
+		if vpn.Connection.VpnGatewayId != nil {
+			connection.GatewayType = gatewayTypeVirtualPrivateGateway
+			connection.GatewayID = *vpn.Connection.VpnGatewayId
+		} else if vpn.Connection.TransitGatewayId != nil {
+			connection.GatewayType = gatewayTypeTransitGateway
+			connection.GatewayID = *vpn.Connection.TransitGatewayId
+		} else {
+			continue
+		}
+		connections = append(connections, connection)
+	}
+
+	associations := map[string][]dctypes.DirectConnectGatewayAssociation{}
+	for _, association := range hybrid.DirectConnectGatewayAssociations {
+		if association.AssociationState == dctypes.DirectConnectGatewayAssociationStateAssociated {
+			gatewayID := aws.ToString(association.DirectConnectGatewayId)
+			associations[gatewayID] = append(associations[gatewayID], association)
+		}
+	}
+	for _, virtualInterface := range hybrid.VirtualInterfaces {
+		switch virtualInterface.VirtualInterfaceState {
+		case dctypes.VirtualInterfaceStateDeleting, dctypes.VirtualInterfaceStateDeleted, dctypes.VirtualInterfaceStateRejected:
+			continue
+		}
+		virtualInterfaceID := aws.ToString(virtualInterface.VirtualInterfaceId)
+		if virtualInterface.VirtualGatewayId != nil && *virtualInterface.VirtualGatewayId != "" {
+			connections = append(connections, HybridConnection{
+				Type:         hybridTypeDirectConnect,
+				ConnectionID: virtualInterfaceID,
+				GatewayType:  gatewayTypeVirtualPrivateGateway,
+				GatewayID:    *virtualInterface.VirtualGatewayId,
+			})
+		}
+		directConnectGatewayID := aws.ToString(virtualInterface.DirectConnectGatewayId)
+		for _, association := range associations[directConnectGatewayID] {
+			if association.AssociatedGateway == nil {
+				continue
+			}
+			gatewayType := gatewayTypeVirtualPrivateGateway
+			if association.AssociatedGateway.Type == dctypes.GatewayTypeTransitGateway {
+				gatewayType = gatewayTypeTransitGateway
+			}
+			connections = append(connections, HybridConnection{
+				Type:                   hybridTypeDirectConnect,
+				ConnectionID:           virtualInterfaceID,
+				GatewayType:            gatewayType,
+				GatewayID:              aws.ToString(association.AssociatedGateway.Id),
+				DirectConnectGatewayID: directConnectGatewayID,
+			})
+		}
+	}
+
+	for i := range vpcs {
+		vpcID := aws.ToString(vpcs[i].VPC.VpcId)
+		vpcs[i].HybridConnections = []HybridConnection{}
+		for _, connection := range connections {
+			if gatewayVPCs[connection.GatewayID][vpcID] {
+				vpcs[i].HybridConnections = append(vpcs[i].HybridConnections, connection)
+			}
+		}
+	}
+}
+
+// newVPNConnection wraps a VPN connection with a summary of its tunnels, pairing the telemetry and tunnel options of
+// each tunnel by outside IP address. The pre-shared keys and customer gateway configuration are removed.
+func newVPNConnection(connection types.VpnConnection, region string) VPNConnection {
+	connection.CustomerGatewayConfiguration = nil
+
+	ikeVersions := map[string][]string{}
+	if connection.Options != nil {
+		tunnelOptions := make([]types.TunnelOption, len(connection.Options.TunnelOptions))
+		for i, tunnel := range connection.Options.TunnelOptions {
+			tunnel.PreSharedKey = nil
+			tunnelOptions[i] = tunnel
+			versions := []string{}
+			for _, version := range tunnel.IkeVersions {
+				versions = append(versions, aws.ToString(version.Value))
+			}
+			ikeVersions[aws.ToString(tunnel.OutsideIpAddress)] = versions
+		}
+		options := *connection.Options
+		options.TunnelOptions = tunnelOptions
+		connection.Options = &options
+	}
+
+	tunnels := []VPNTunnel{}
+	for _, telemetry := range connection.VgwTelemetry {
+		outsideIPAddress := aws.ToString(telemetry.OutsideIpAddress)
+		versions := ikeVersions[outsideIPAddress]
+		if versions == nil {
+			versions = []string{}
+		}
+		tunnels = append(tunnels, VPNTunnel{
+			OutsideIPAddress: outsideIPAddress,
+			Status:           string(telemetry.Status),
+			StatusMessage:    aws.ToString(telemetry.StatusMessage),
+			LastStatusChange: telemetry.LastStatusChange,
+			IKEVersions:      versions,
+		})
+	}
+
+	return VPNConnection{Connection: connection, Tunnels: tunnels, Region: region}
+}
+
+// redactVirtualInterface removes the BGP authentication keys and router configuration from a virtual interface.
+func redactVirtualInterface(virtualInterface dctypes.VirtualInterface) dctypes.VirtualInterface {
+	virtualInterface.AuthKey = nil
+	virtualInterface.CustomerRouterConfig = nil
+	bgpPeers := make([]dctypes.BGPPeer, len(virtualInterface.BgpPeers))
+	for i, peer := range virtualInterface.BgpPeers {
+		peer.AuthKey = nil
+		bgpPeers[i] = peer
+	}
+	virtualInterface.BgpPeers = bgpPeers
+	return virtualInterface
+}
+
+// describeTransitGateways lists the transit gateways owned by or shared with the account alongside their attachments
+// and route tables.
+func describeTransitGateways(ctx context.Context, svc *ec2.Client, region string) ([]TransitGateway, []string) {
+	transitGateways := []TransitGateway{}
+	errors := []string{}
+
+	paginator := ec2.NewDescribeTransitGatewaysPaginator(svc, &ec2.DescribeTransitGatewaysInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("error describing transit gateways: %v", err))
+			break
+		}
+		for _, transitGateway := range page.TransitGateways {
+			if transitGateway.State == types.TransitGatewayStateDeleted {
+				continue
+			}
+			transitGateways = append(transitGateways, TransitGateway{
+				TransitGateway: transitGateway,
+				Attachments:    []types.TransitGatewayAttachment{},
+				RouteTables:    []TransitGatewayRouteTable{},
+				Region:         region,
+			})
+		}
+	}
+
+	index := map[string]*TransitGateway{}
+	for i := range transitGateways {
+		index[aws.ToString(transitGateways[i].TransitGateway.TransitGatewayId)] = &transitGateways[i]
+	}
+
+	attachmentPaginator := ec2.NewDescribeTransitGatewayAttachmentsPaginator(svc, &ec2.DescribeTransitGatewayAttachmentsInput{})
+	for attachmentPaginator.HasMorePages() {
+		page, err := attachmentPaginator.NextPage(ctx)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("error describing transit gateway attachments: %v", err))
+			break
+		}
+		for _, attachment := range page.TransitGatewayAttachments {
+			if transitGateway, ok := index[aws.ToString(attachment.TransitGatewayId)]; ok {
+				transitGateway.Attachments = append(transitGateway.Attachments, attachment)
+			}
+		}
+	}
+
+	routeTablePaginator := ec2.NewDescribeTransitGatewayRouteTablesPaginator(svc, &ec2.DescribeTransitGatewayRouteTablesInput{})
+	for routeTablePaginator.HasMorePages() {
+		page, err := routeTablePaginator.NextPage(ctx)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("error describing transit gateway route tables: %v", err))
+			break
+		}
+		for _, routeTable := range page.TransitGatewayRouteTables {
+			transitGateway, ok := index[aws.ToString(routeTable.TransitGatewayId)]
+			if !ok {
+				continue
+			}
+			routes, err := searchTransitGatewayRoutes(ctx, svc, aws.ToString(routeTable.TransitGatewayRouteTableId))
+			if err != nil {
+				errors = append(errors, err.Error())
+			}
+			transitGateway.RouteTables = append(transitGateway.RouteTables, TransitGatewayRouteTable{
+				RouteTable: routeTable,
+				Routes:     routes,
+			})
+		}
+	}
+
+	return transitGateways, errors
+}
+
+// searchTransitGatewayRoutes returns the active and blackhole routes of a transit gateway route table. The API does not
+// paginate, so an error is returned alongside the routes when the route table holds more routes than a single call
+// returns.
+func searchTransitGatewayRoutes(ctx context.Context, svc *ec2.Client, routeTableID string) ([]types.TransitGatewayRoute, error) {
+	output, err := svc.SearchTransitGatewayRoutes(ctx, &ec2.SearchTransitGatewayRoutesInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: []string{string(types.TransitGatewayRouteStateActive), string(types.TransitGatewayRouteStateBlackhole)},
+			},
+		},
+		MaxResults: aws.Int32(maxTransitGatewayRoutes),
+	})
+	if err != nil {
+		return []types.TransitGatewayRoute{}, fmt.Errorf("error searching routes for transit gateway route table %s: %v", routeTableID, err)
+	}
+	routes := output.Routes
+	if routes == nil {
+		routes = []types.TransitGatewayRoute{}
+	}
+	if aws.ToBool(output.AdditionalRoutesAvailable) {
+		return routes, fmt.Errorf("transit gateway route table %s has more than %d routes, only the first %d are reported", routeTableID, maxTransitGatewayRoutes, maxTransitGatewayRoutes)
+	}
+	return routes, nil
+}
+
+func describeDirectConnectGatewayAssociations(ctx context.Context, svc *directconnect.Client, gatewayID string) ([]dctypes.DirectConnectGatewayAssociation, error) {
+	var associations []dctypes.DirectConnectGatewayAssociation
+	input := &directconnect.DescribeDirectConnectGatewayAssociationsInput{DirectConnectGatewayId: aws.String(gatewayID)}
+	for {
+		output, err := svc.DescribeDirectConnectGatewayAssociations(ctx, input)
+		if err != nil {
+			return associations, fmt.Errorf("error describing associations for Direct Connect gateway %s: %v", gatewayID, err)
+		}
+		associations = append(associations, output.DirectConnectGatewayAssociations...)
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return associations, nil
+}
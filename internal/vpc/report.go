@@ -2,19 +2,126 @@
 package vpc
 
 import (
+	"time"
+
+	dctypes "github.com/aws/aws-sdk-go-v2/service/directconnect/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 )
 
-// The Instance struct contains the VPC data that was enumerated, wrapping the AWS values
+// The Subnet struct wraps an AWS subnet with the IDs of the route table and network ACL that govern its traffic.
+// A subnet is Public when its route table routes traffic to an internet gateway. Public is nil when the route tables of
+// the subnet's region could not be described. FlowLogIDs only contains flow logs created directly on the subnet; flow
+// logs created on the VPC also cover the subnet.
+type Subnet struct {
+	Subnet             types.Subnet            `json:"subnet" yaml:"subnet"`
+	RouteTableID       string                  `json:"route_table_id" yaml:"route_table_id"`
+	NetworkACLID       string                  `json:"network_acl_id" yaml:"network_acl_id"`
+	NetworkACLEntries  []types.NetworkAclEntry `json:"network_acl_entries" yaml:"network_acl_entries"`
+	AllowAllNetworkACL bool                    `json:"allow_all_network_acl" yaml:"allow_all_network_acl"`
+	FlowLogIDs         []string                `json:"flow_log_ids" yaml:"flow_log_ids"`
+	Public             *bool                   `json:"public" yaml:"public"`
+}
+
+// The Topology struct contains the networking resources that belong to a single VPC. Resources reference each other
+// by their AWS IDs, e.g. a Subnet's RouteTableID matches the RouteTableId of one of the RouteTables. RouteTablesUnknown
+// and FlowLogsUnknown are set when the route tables or flow logs of the VPC's region could not be described, in which
+// case RouteTables or FlowLogs may be incomplete.
+type Topology struct {
+	Subnets                    []Subnet                            `json:"subnets" yaml:"subnets"`
+	RouteTables                []types.RouteTable                  `json:"route_tables" yaml:"route_tables"`
+	InternetGateways           []types.InternetGateway             `json:"internet_gateways" yaml:"internet_gateways"`
+	NATGateways                []types.NatGateway                  `json:"nat_gateways" yaml:"nat_gateways"`
+	EgressOnlyInternetGateways []types.EgressOnlyInternetGateway   `json:"egress_only_internet_gateways" yaml:"egress_only_internet_gateways"`
+	Endpoints                  []types.VpcEndpoint                 `json:"endpoints" yaml:"endpoints"`
+	PeeringConnections         []types.VpcPeeringConnection        `json:"peering_connections" yaml:"peering_connections"`
+	TransitGatewayAttachments  []types.TransitGatewayVpcAttachment `json:"transit_gateway_attachments" yaml:"transit_gateway_attachments"`
+	NetworkACLs                []types.NetworkAcl                  `json:"network_acls" yaml:"network_acls"`
+	FlowLogs                   []types.FlowLog                     `json:"flow_logs" yaml:"flow_logs"`
+	RouteTablesUnknown         bool                                `json:"route_tables_unknown" yaml:"route_tables_unknown"`
+	FlowLogsUnknown            bool                                `json:"flow_logs_unknown" yaml:"flow_logs_unknown"`
+}
+
+// The HybridConnection struct describes a path from an on-premises network into a VPC: a site-to-site VPN connection
+// or Direct Connect virtual interface that terminates on a virtual private gateway or transit gateway attached to the
+// VPC. DirectConnectGatewayID is set when a virtual interface reaches the gateway through a Direct Connect gateway.
+type HybridConnection struct {
+	Type                   string `json:"type" yaml:"type"`
+	ConnectionID           string `json:"connection_id" yaml:"connection_id"`
+	GatewayType            string `json:"gateway_type" yaml:"gateway_type"`
+	GatewayID              string `json:"gateway_id" yaml:"gateway_id"`
+	DirectConnectGatewayID string `json:"direct_connect_gateway_id,omitempty" yaml:"direct_connect_gateway_id,omitempty"`
+}
+
+// The Instance struct contains the VPC data that was enumerated, wrapping the AWS values, alongside the topology of
+// the networking resources within the VPC and the hybrid connections that reach it
 type Instance struct {
-	VPC    types.Vpc `json:"vpc" yaml:"vpc"`
-	Region string    `json:"region" yaml:"region"`
+	VPC               types.Vpc          `json:"vpc" yaml:"vpc"`
+	Region            string             `json:"region" yaml:"region"`
+	FlowLogIDs        []string           `json:"flow_log_ids" yaml:"flow_log_ids"`
+	Topology          Topology           `json:"topology" yaml:"topology"`
+	HybridConnections []HybridConnection `json:"hybrid_connections" yaml:"hybrid_connections"`
+}
+
+// The TransitGatewayRouteTable struct wraps a transit gateway route table with its active and blackhole routes.
+type TransitGatewayRouteTable struct {
+	RouteTable types.TransitGatewayRouteTable `json:"route_table" yaml:"route_table"`
+	Routes     []types.TransitGatewayRoute    `json:"routes" yaml:"routes"`
+}
+
+// The TransitGateway struct wraps a transit gateway with all of its attachments (VPC, VPN, Direct Connect gateway and
+// peering) and its route tables.
+type TransitGateway struct {
+	TransitGateway types.TransitGateway             `json:"transit_gateway" yaml:"transit_gateway"`
+	Attachments    []types.TransitGatewayAttachment `json:"attachments" yaml:"attachments"`
+	RouteTables    []TransitGatewayRouteTable       `json:"route_tables" yaml:"route_tables"`
+	Region         string                           `json:"region" yaml:"region"`
+}
+
+// The VPNTunnel struct summarizes the status and IKE versions of one of the two tunnels of a site-to-site VPN
+// connection.
+type VPNTunnel struct {
+	OutsideIPAddress string     `json:"outside_ip_address" yaml:"outside_ip_address"`
+	Status           string     `json:"status" yaml:"status"`
+	StatusMessage    string     `json:"status_message" yaml:"status_message"`
+	LastStatusChange *time.Time `json:"last_status_change" yaml:"last_status_change"`
+	IKEVersions      []string   `json:"ike_versions" yaml:"ike_versions"`
+}
+
+// The VPNConnection struct wraps a site-to-site VPN connection with a summary of its tunnels. Pre-shared keys and the
+// customer gateway configuration are removed from the wrapped connection.
+type VPNConnection struct {
+	Connection types.VpnConnection `json:"connection" yaml:"connection"`
+	Tunnels    []VPNTunnel         `json:"tunnels" yaml:"tunnels"`
+	Region     string              `json:"region" yaml:"region"`
+}
+
+// The HybridConnectivity struct contains the resources that connect on-premises networks to the account's VPCs.
+// BGP authentication keys are removed from the Direct Connect virtual interfaces.
+type HybridConnectivity struct {
+	TransitGateways                  []TransitGateway                          `json:"transit_gateways" yaml:"transit_gateways"`
+	VPNGateways                      []types.VpnGateway                        `json:"vpn_gateways" yaml:"vpn_gateways"`
+	VPNConnections                   []VPNConnection                           `json:"vpn_connections" yaml:"vpn_connections"`
+	CustomerGateways                 []types.CustomerGateway                   `json:"customer_gateways" yaml:"customer_gateways"`
+	VirtualInterfaces                []dctypes.VirtualInterface                `json:"virtual_interfaces" yaml:"virtual_interfaces"`
+	DirectConnectGatewayAssociations []dctypes.DirectConnectGatewayAssociation `json:"direct_connect_gateway_associations" yaml:"direct_connect_gateway_associations"`
+}
+
+// The Audit struct surfaces the VPCs and subnets that fall short of flow log and network ACL requirements. A subnet
+// is only reported as lacking flow logs when neither the subnet nor its VPC has an active flow log. VPCs whose flow logs
+// could not be described are left out of the flow log audit.
+type Audit struct {
+	VPCsWithoutFlowLogs           []string `json:"vpcs_without_flow_logs" yaml:"vpcs_without_flow_logs"`
+	SubnetsWithoutFlowLogs        []string `json:"subnets_without_flow_logs" yaml:"subnets_without_flow_logs"`
+	SubnetsWithAllowAllNetworkACL []string `json:"subnets_with_allow_all_network_acl" yaml:"subnets_with_allow_all_network_acl"`
 }
 
-// The Report struct contains the account ID that the VPCs were discovered in, the resources themselves,
-// and any non-fatal errors that occurred during the execution of the `methodaws vpc enumerate` subcommand.
+// The Report struct contains the account ID that the VPCs were discovered in, the resources themselves, the hybrid
+// connectivity resources, the flow log and network ACL audit, and any non-fatal errors that occurred during the
+// execution of the `methodaws vpc enumerate` subcommand.
 type Report struct {
-	AccountID string     `json:"account_id" yaml:"account_id"`
-	VPCs      []Instance `json:"vpcs" yaml:"vpcs"`
-	Errors    []string   `json:"errors" yaml:"errors"`
+	AccountID          string             `json:"account_id" yaml:"account_id"`
+	VPCs               []Instance         `json:"vpcs" yaml:"vpcs"`
+	HybridConnectivity HybridConnectivity `json:"hybrid_connectivity" yaml:"hybrid_connectivity"`
+	Audit              Audit              `json:"audit" yaml:"audit"`
+	Errors             []string           `json:"errors" yaml:"errors"`
 }
@@ -0,0 +1,436 @@
+package vpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// newTopology returns an empty Topology so that every list is serialized as an empty array rather than null.
+func newTopology() *Topology {
+	return &Topology{
+		Subnets:                    []Subnet{},
+		RouteTables:                []types.RouteTable{},
+		InternetGateways:           []types.InternetGateway{},
+		NATGateways:                []types.NatGateway{},
+		EgressOnlyInternetGateways: []types.EgressOnlyInternetGateway{},
+		Endpoints:                  []types.VpcEndpoint{},
+		PeeringConnections:         []types.VpcPeeringConnection{},
+		TransitGatewayAttachments:  []types.TransitGatewayVpcAttachment{},
+		NetworkACLs:                []types.NetworkAcl{},
+		FlowLogs:                   []types.FlowLog{},
+	}
+}
+
+// buildTopologyForRegion describes every networking resource in a region and groups the results by VPC ID. Each
+// resource type is described once for the whole region rather than once per VPC. A failure to describe one resource
+// type is recorded as a non-fatal error and the rest of the topology is still returned.
+func buildTopologyForRegion(ctx context.Context, svc *ec2.Client, vpcIDs []string) (map[string]*Topology, []string) {
+	topologies := map[string]*Topology{}
+	for _, vpcID := range vpcIDs {
+		topologies[vpcID] = newTopology()
+	}
+	errors := []string{}
+
+	// lookup returns the topology for a VPC, ignoring resources belonging to VPCs that were not enumerated
+	lookup := func(vpcID *string) (*Topology, bool) {
+		topology, ok := topologies[aws.ToString(vpcID)]
+		return topology, ok
+	}
+
+	routeTables, err := describeRouteTables(ctx, svc)
+	if err != nil {
+		// Without the route tables every subnet would appear private, so mark them as unknown instead
+		errors = append(errors, err.Error())
+		for _, topology := range topologies {
+			topology.RouteTablesUnknown = true
+		}
+	}
+	for _, routeTable := range routeTables {
+		if topology, ok := lookup(routeTable.VpcId); ok {
+			topology.RouteTables = append(topology.RouteTables, routeTable)
+		}
+	}
+
+	networkACLs, err := describeNetworkACLs(ctx, svc)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	for _, networkACL := range networkACLs {
+		if topology, ok := lookup(networkACL.VpcId); ok {
+			topology.NetworkACLs = append(topology.NetworkACLs, networkACL)
+		}
+	}
+
+	subnets, err := describeSubnets(ctx, svc)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	subnetVPCs := map[string]string{}
+	for _, subnet := range subnets {
+		subnetVPCs[aws.ToString(subnet.SubnetId)] = aws.ToString(subnet.VpcId)
+	}
+
+	// Flow logs can be attached to a VPC, a subnet or a network interface, so resolve each resource back to its VPC
+	flowLogs, err := describeFlowLogs(ctx, svc)
+	if err != nil {
+		// Without the flow logs every VPC and subnet would appear uncovered, so mark them as unknown for the audit
+		errors = append(errors, err.Error())
+		for _, topology := range topologies {
+			topology.FlowLogsUnknown = true
+		}
+	}
+	networkInterfaceVPCs := map[string]string{}
+	if hasNetworkInterfaceFlowLog(flowLogs) {
+		networkInterfaces, err := describeNetworkInterfaces(ctx, svc)
+		if err != nil {
+			errors = append(errors, err.Error())
+		}
+		for _, networkInterface := range networkInterfaces {
+			networkInterfaceVPCs[aws.ToString(networkInterface.NetworkInterfaceId)] = aws.ToString(networkInterface.VpcId)
+		}
+	}
+	subnetFlowLogs := map[string][]string{}
+	for _, flowLog := range flowLogs {
+		resourceID := aws.ToString(flowLog.ResourceId)
+		vpcID := resourceID
+		if subnetVPC, ok := subnetVPCs[resourceID]; ok {
+			vpcID = subnetVPC
+			subnetFlowLogs[resourceID] = append(subnetFlowLogs[resourceID], aws.ToString(flowLog.FlowLogId))
+		} else if networkInterfaceVPC, ok := networkInterfaceVPCs[resourceID]; ok {
+			vpcID = networkInterfaceVPC
+		}
+		if topology, ok := topologies[vpcID]; ok {
+			topology.FlowLogs = append(topology.FlowLogs, flowLog)
+		}
+	}
+
+	for _, subnet := range subnets {
+		if topology, ok := lookup(subnet.VpcId); ok {
+			subnetID := aws.ToString(subnet.SubnetId)
+			routeTable := routeTableForSubnet(topology.RouteTables, subnetID)
+			networkACL := networkACLForSubnet(topology.NetworkACLs, subnetID)
+			flowLogIDs := subnetFlowLogs[subnetID]
+			if flowLogIDs == nil {
+				flowLogIDs = []string{}
+			}
+			entries := networkACL.Entries
+			if entries == nil {
+				entries = []types.NetworkAclEntry{}
+			}
+			var public *bool
+			if !topology.RouteTablesUnknown {
+				public = aws.Bool(hasInternetGatewayRoute(routeTable))
+			}
+			topology.Subnets = append(topology.Subnets, Subnet{
+				Subnet:             subnet,
+				RouteTableID:       aws.ToString(routeTable.RouteTableId),
+				NetworkACLID:       aws.ToString(networkACL.NetworkAclId),
+				NetworkACLEntries:  entries,
+				AllowAllNetworkACL: allowsAllInbound(networkACL),
+				FlowLogIDs:         flowLogIDs,
+				Public:             public,
+			})
+		}
+	}
+
+	internetGateways, err := describeInternetGateways(ctx, svc)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	for _, internetGateway := range internetGateways {
+		for _, attachment := range internetGateway.Attachments {
+			if topology, ok := lookup(attachment.VpcId); ok {
+				topology.InternetGateways = append(topology.InternetGateways, internetGateway)
+			}
+		}
+	}
+
+	natGateways, err := describeNATGateways(ctx, svc)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	for _, natGateway := range natGateways {
+		if topology, ok := lookup(natGateway.VpcId); ok {
+			topology.NATGateways = append(topology.NATGateways, natGateway)
+		}
+	}
+
+	egressOnlyGateways, err := describeEgressOnlyInternetGateways(ctx, svc)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	for _, egressOnlyGateway := range egressOnlyGateways {
+		for _, attachment := range egressOnlyGateway.Attachments {
+			if topology, ok := lookup(attachment.VpcId); ok {
+				topology.EgressOnlyInternetGateways = append(topology.EgressOnlyInternetGateways, egressOnlyGateway)
+			}
+		}
+	}
+
+	endpoints, err := describeVPCEndpoints(ctx, svc)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	for _, endpoint := range endpoints {
+		if topology, ok := lookup(endpoint.VpcId); ok {
+			topology.Endpoints = append(topology.Endpoints, endpoint)
+		}
+	}
+
+	peeringConnections, err := describePeeringConnections(ctx, svc)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	for _, peeringConnection := range peeringConnections {
+		// A peering connection between two VPCs in the same region belongs to both topologies
+		var requesterVPCID *string
+		if peeringConnection.RequesterVpcInfo != nil {
+			requesterVPCID = peeringConnection.RequesterVpcInfo.VpcId
+			if topology, ok := lookup(requesterVPCID); ok {
+				topology.PeeringConnections = append(topology.PeeringConnections, peeringConnection)
+			}
+		}
+		if peeringConnection.AccepterVpcInfo != nil && aws.ToString(peeringConnection.AccepterVpcInfo.VpcId) != aws.ToString(requesterVPCID) {
+			if topology, ok := lookup(peeringConnection.AccepterVpcInfo.VpcId); ok {
+				topology.PeeringConnections = append(topology.PeeringConnections, peeringConnection)
+			}
+		}
+	}
+
+	transitGatewayAttachments, err := describeTransitGatewayVPCAttachments(ctx, svc)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	for _, attachment := range transitGatewayAttachments {
+		if topology, ok := lookup(attachment.VpcId); ok {
+			topology.TransitGatewayAttachments = append(topology.TransitGatewayAttachments, attachment)
+		}
+	}
+
+	return topologies, errors
+}
+
+// routeTableForSubnet returns the route table explicitly associated with a subnet, falling back to the main route
+// table of the VPC when the subnet has no explicit association.
+func routeTableForSubnet(routeTables []types.RouteTable, subnetID string) types.RouteTable {
+	var main types.RouteTable
+	for _, routeTable := range routeTables {
+		for _, association := range routeTable.Associations {
+			if aws.ToString(association.SubnetId) == subnetID {
+				return routeTable
+			}
+			if aws.ToBool(association.Main) {
+				main = routeTable
+			}
+		}
+	}
+	return main
+}
+
+// networkACLForSubnet returns the network ACL associated with a subnet. Every subnet is associated with exactly one
+// network ACL.
+func networkACLForSubnet(networkACLs []types.NetworkAcl, subnetID string) types.NetworkAcl {
+	for _, networkACL := range networkACLs {
+		for _, association := range networkACL.Associations {
+			if aws.ToString(association.SubnetId) == subnetID {
+				return networkACL
+			}
+		}
+	}
+	return types.NetworkAcl{}
+}
+
+// allowsAllInbound reports whether a network ACL allows all inbound traffic from the internet, i.e. an entry allowing
+// every protocol from 0.0.0.0/0 or ::/0 is evaluated before any entry denying every protocol from the same range. IPv4
+// and IPv6 entries are evaluated separately, and deny entries for narrower ranges or single protocols do not stop the
+// evaluation.
+func allowsAllInbound(networkACL types.NetworkAcl) bool {
+	entries := append([]types.NetworkAclEntry{}, networkACL.Entries...)
+	sort.Slice(entries, func(i, j int) bool { return aws.ToInt32(entries[i].RuleNumber) < aws.ToInt32(entries[j].RuleNumber) })
+
+	ipv4 := func(entry types.NetworkAclEntry) string { return aws.ToString(entry.CidrBlock) }
+	ipv6 := func(entry types.NetworkAclEntry) string { return aws.ToString(entry.Ipv6CidrBlock) }
+	return allowsAllInboundFrom(entries, "0.0.0.0/0", ipv4) || allowsAllInboundFrom(entries, "::/0", ipv6)
+}
+
+// allowsAllInboundFrom evaluates inbound entries, sorted by rule number, for a single address family and reports
+// whether an entry allowing every protocol from anyCIDR comes before an entry denying every protocol from it.
+func allowsAllInboundFrom(entries []types.NetworkAclEntry, anyCIDR string, cidr func(types.NetworkAclEntry) string) bool {
+	for _, entry := range entries {
+		if aws.ToBool(entry.Egress) || cidr(entry) != anyCIDR || aws.ToString(entry.Protocol) != "-1" {
+			continue
+		}
+		return entry.RuleAction == types.RuleActionAllow
+	}
+	return false
+}
+
+// hasNetworkInterfaceFlowLog reports whether any flow log was created directly on a network interface.
+func hasNetworkInterfaceFlowLog(flowLogs []types.FlowLog) bool {
+	for _, flowLog := range flowLogs {
+		if strings.HasPrefix(aws.ToString(flowLog.ResourceId), "eni-") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasInternetGatewayRoute reports whether a route table routes traffic to an internet gateway, which is what makes
+// the subnets associated with it public.
+func hasInternetGatewayRoute(routeTable types.RouteTable) bool {
+	for _, route := range routeTable.Routes {
+		if strings.HasPrefix(aws.ToString(route.GatewayId), "igw-") && route.State != types.RouteStateBlackhole {
+			return true
+		}
+	}
+	return false
+}
+
+func describeSubnets(ctx context.Context, svc *ec2.Client) ([]types.Subnet, error) {
+	var subnets []types.Subnet
+	paginator := ec2.NewDescribeSubnetsPaginator(svc, &ec2.DescribeSubnetsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return subnets, fmt.Errorf("error describing subnets: %v", err)
+		}
+		subnets = append(subnets, page.Subnets...)
+	}
+	return subnets, nil
+}
+
+func describeRouteTables(ctx context.Context, svc *ec2.Client) ([]types.RouteTable, error) {
+	var routeTables []types.RouteTable
+	paginator := ec2.NewDescribeRouteTablesPaginator(svc, &ec2.DescribeRouteTablesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return routeTables, fmt.Errorf("error describing route tables: %v", err)
+		}
+		routeTables = append(routeTables, page.RouteTables...)
+	}
+	return routeTables, nil
+}
+
+func describeNetworkACLs(ctx context.Context, svc *ec2.Client) ([]types.NetworkAcl, error) {
+	var networkACLs []types.NetworkAcl
+	paginator := ec2.NewDescribeNetworkAclsPaginator(svc, &ec2.DescribeNetworkAclsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return networkACLs, fmt.Errorf("error describing network ACLs: %v", err)
+		}
+		networkACLs = append(networkACLs, page.NetworkAcls...)
+	}
+	return networkACLs, nil
+}
+
+func describeInternetGateways(ctx context.Context, svc *ec2.Client) ([]types.InternetGateway, error) {
+	var internetGateways []types.InternetGateway
+	paginator := ec2.NewDescribeInternetGatewaysPaginator(svc, &ec2.DescribeInternetGatewaysInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return internetGateways, fmt.Errorf("error describing internet gateways: %v", err)
+		}
+		internetGateways = append(internetGateways, page.InternetGateways...)
+	}
+	return internetGateways, nil
+}
+
+func describeNATGateways(ctx context.Context, svc *ec2.Client) ([]types.NatGateway, error) {
+	var natGateways []types.NatGateway
+	paginator := ec2.NewDescribeNatGatewaysPaginator(svc, &ec2.DescribeNatGatewaysInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return natGateways, fmt.Errorf("error describing NAT gateways: %v", err)
+		}
+		natGateways = append(natGateways, page.NatGateways...)
+	}
+	return natGateways, nil
+}
+
+func describeEgressOnlyInternetGateways(ctx context.Context, svc *ec2.Client) ([]types.EgressOnlyInternetGateway, error) {
+	var egressOnlyGateways []types.EgressOnlyInternetGateway
+	paginator := ec2.NewDescribeEgressOnlyInternetGatewaysPaginator(svc, &ec2.DescribeEgressOnlyInternetGatewaysInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return egressOnlyGateways, fmt.Errorf("error describing egress-only internet gateways: %v", err)
+		}
+		egressOnlyGateways = append(egressOnlyGateways, page.EgressOnlyInternetGateways...)
+	}
+	return egressOnlyGateways, nil
+}
+
+func describeVPCEndpoints(ctx context.Context, svc *ec2.Client) ([]types.VpcEndpoint, error) {
+	var endpoints []types.VpcEndpoint
+	paginator := ec2.NewDescribeVpcEndpointsPaginator(svc, &ec2.DescribeVpcEndpointsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return endpoints, fmt.Errorf("error describing VPC endpoints: %v", err)
+		}
+		endpoints = append(endpoints, page.VpcEndpoints...)
+	}
+	return endpoints, nil
+}
+
+func describePeeringConnections(ctx context.Context, svc *ec2.Client) ([]types.VpcPeeringConnection, error) {
+	var peeringConnections []types.VpcPeeringConnection
+	paginator := ec2.NewDescribeVpcPeeringConnectionsPaginator(svc, &ec2.DescribeVpcPeeringConnectionsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return peeringConnections, fmt.Errorf("error describing VPC peering connections: %v", err)
+		}
+		peeringConnections = append(peeringConnections, page.VpcPeeringConnections...)
+	}
+	return peeringConnections, nil
+}
+
+func describeTransitGatewayVPCAttachments(ctx context.Context, svc *ec2.Client) ([]types.TransitGatewayVpcAttachment, error) {
+	var attachments []types.TransitGatewayVpcAttachment
+	paginator := ec2.NewDescribeTransitGatewayVpcAttachmentsPaginator(svc, &ec2.DescribeTransitGatewayVpcAttachmentsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return attachments, fmt.Errorf("error describing transit gateway VPC attachments: %v", err)
+		}
+		attachments = append(attachments, page.TransitGatewayVpcAttachments...)
+	}
+	return attachments, nil
+}
+
+func describeFlowLogs(ctx context.Context, svc *ec2.Client) ([]types.FlowLog, error) {
+	var flowLogs []types.FlowLog
+	paginator := ec2.NewDescribeFlowLogsPaginator(svc, &ec2.DescribeFlowLogsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return flowLogs, fmt.Errorf("error describing flow logs: %v", err)
+		}
+		flowLogs = append(flowLogs, page.FlowLogs...)
+	}
+	return flowLogs, nil
+}
+
+func describeNetworkInterfaces(ctx context.Context, svc *ec2.Client) ([]types.NetworkInterface, error) {
+	var networkInterfaces []types.NetworkInterface
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(svc, &ec2.DescribeNetworkInterfacesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return networkInterfaces, fmt.Errorf("error describing network interfaces: %v", err)
+		}
+		networkInterfaces = append(networkInterfaces, page.NetworkInterfaces...)
+	}
+	return networkInterfaces, nil
+}
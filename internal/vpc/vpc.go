@@ -9,8 +9,11 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 )
 
-// EnumerateVPCForRegion lists the VPCs available to the caller for a particular regionand returns a Report struct. The Report
-// contains all non-fatal errors that occurred during the execution of the `methodaws vpc enumerate` subcommand.
+// EnumerateVPCForRegion lists the VPCs available to the caller for a particular regionand returns a Report struct. Each
+// VPC includes its topology: subnets, route tables, gateways, endpoints, peering connections, transit gateway
+// attachments and network ACLs, and the hybrid connections that reach it from on-premises networks through VPN
+// connections and Direct Connect virtual interfaces. The Report contains all non-fatal errors that occurred during the
+// execution of the `methodaws vpc enumerate` subcommand.
 // EnumerateVPCForRegion will return an error if the account ID cannot be retrieved.
 func EnumerateVPCForRegion(ctx context.Context, cfg aws.Config, region string) (report Report, err error) {
 	cfg.Region = region
@@ -42,13 +45,84 @@ func EnumerateVPCForRegion(ctx context.Context, cfg aws.Config, region string) (
 		}
 	}
 
+	vpcIDs := []string{}
+	for _, vpc := range vpcs {
+		vpcIDs = append(vpcIDs, aws.ToString(vpc.VPC.VpcId))
+	}
+	topologies, topologyErrors := buildTopologyForRegion(ctx, svc, vpcIDs)
+	errors = append(errors, topologyErrors...)
+	for i := range vpcs {
+		vpcs[i].Topology = *topologies[aws.ToString(vpcs[i].VPC.VpcId)]
+		vpcs[i].FlowLogIDs = []string{}
+		for _, flowLog := range vpcs[i].Topology.FlowLogs {
+			if aws.ToString(flowLog.ResourceId) == aws.ToString(vpcs[i].VPC.VpcId) {
+				vpcs[i].FlowLogIDs = append(vpcs[i].FlowLogIDs, aws.ToString(flowLog.FlowLogId))
+			}
+		}
+	}
+
+	hybrid, hybridErrors := enumerateHybridConnectivityForRegion(ctx, cfg, region)
+	errors = append(errors, hybridErrors...)
+	linkHybridConnections(vpcs, hybrid)
+
 	return Report{
-		AccountID: *accountID,
-		VPCs:      vpcs,
-		Errors:    errors,
+		AccountID:          *accountID,
+		VPCs:               vpcs,
+		HybridConnectivity: hybrid,
+		Audit:              auditVPCs(vpcs),
+		Errors:             errors,
 	}, nil
 }
 
+// auditVPCs surfaces the VPCs without an active VPC-level flow log, the subnets covered by neither an active subnet
+// nor VPC-level flow log, and the subnets whose network ACL allows all inbound traffic from the internet. The flow log
+// audit skips VPCs whose flow logs are unknown.
+func auditVPCs(vpcs []Instance) Audit {
+	audit := Audit{
+		VPCsWithoutFlowLogs:           []string{},
+		SubnetsWithoutFlowLogs:        []string{},
+		SubnetsWithAllowAllNetworkACL: []string{},
+	}
+
+	for _, instance := range vpcs {
+		active := map[string]bool{}
+		for _, flowLog := range instance.Topology.FlowLogs {
+			if aws.ToString(flowLog.FlowLogStatus) == "ACTIVE" {
+				active[aws.ToString(flowLog.FlowLogId)] = true
+			}
+		}
+
+		vpcCovered := false
+		for _, flowLogID := range instance.FlowLogIDs {
+			if active[flowLogID] {
+				vpcCovered = true
+			}
+		}
+		flowLogsKnown := !instance.Topology.FlowLogsUnknown
+		if flowLogsKnown && !vpcCovered {
+			audit.VPCsWithoutFlowLogs = append(audit.VPCsWithoutFlowLogs, aws.ToString(instance.VPC.VpcId))
+		}
+
+		for _, subnet := range instance.Topology.Subnets {
+			subnetID := aws.ToString(subnet.Subnet.SubnetId)
+			subnetCovered := vpcCovered
+			for _, flowLogID := range subnet.FlowLogIDs {
+				if active[flowLogID] {
+					subnetCovered = true
+				}
+			}
+			if flowLogsKnown && !subnetCovered {
+				audit.SubnetsWithoutFlowLogs = append(audit.SubnetsWithoutFlowLogs, subnetID)
+			}
+			if subnet.AllowAllNetworkACL {
+				audit.SubnetsWithAllowAllNetworkACL = append(audit.SubnetsWithAllowAllNetworkACL, subnetID)
+			}
+		}
+	}
+
+	return audit
+}
+
 // EnumerateVPC lists the VPCs available to the caller and returns a Report struct for each specified region. The Report
 // contains all non-fatal errors that occurred during the execution of the `methodaws vpc enumerate` subcommand.
 // This method consolidates individual region reports into a single report.
@@ -63,9 +137,10 @@ func EnumerateVPC(ctx context.Context, cfg aws.Config, regions []string) (report
 	}
 
 	report = Report{
-		AccountID: *accountID,
-		VPCs:      []Instance{},
-		Errors:    []string{},
+		AccountID:          *accountID,
+		VPCs:               []Instance{},
+		HybridConnectivity: newHybridConnectivity(),
+		Errors:             []string{},
 	}
 
 	for _, region := range regions {
@@ -77,7 +152,15 @@ func EnumerateVPC(ctx context.Context, cfg aws.Config, regions []string) (report
 		if r.VPCs != nil {
 			report.VPCs = append(report.VPCs, r.VPCs...)
 		}
+		report.HybridConnectivity = mergeHybridConnectivity(report.HybridConnectivity, r.HybridConnectivity)
+		for _, e := range r.Errors {
+			report.Errors = append(report.Errors, fmt.Sprintf("Error in region %s: %s", region, e))
+		}
 	}
+	// Direct Connect gateways can associate a virtual interface in one region with a gateway in another, so the hybrid
+	// connections are linked again once every region has been enumerated
+	linkHybridConnections(report.VPCs, report.HybridConnectivity)
+	report.Audit = auditVPCs(report.VPCs)
 
 	return report, nil
 }
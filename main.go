@@ -26,6 +26,8 @@ func main() {
 	methodaws.InitVPCCommand()
 	methodaws.InitLoadBalancerCommand()
 	methodaws.InitWAFCommand()
+	methodaws.InitSSMCommand()
+	methodaws.InitNetworkCommand()
 
 	if err := methodaws.RootCmd.Execute(); err != nil {
 		os.Exit(1)